@@ -0,0 +1,182 @@
+// Package beanshttp provides a read-only http.Handler exposing the state of a beans container as JSON, similar to the
+// beans endpoint of Spring Boot Actuator. It is meant to be mounted as a debug endpoint:
+//
+//	http.Handle("/debug/beans/", beanshttp.Handler(nil))
+//
+// The handler serves the following resources:
+//
+//	/debug/beans        The registered beans, the active profiles and the dependency graph.
+//	/debug/beans/graph  The dependency graph, as JSON by default, or as Graphviz DOT or Mermaid with the 'format'
+//	                    query parameter set to 'dot' or 'mermaid'.
+//
+// Any other path under the mount point is not found, as long as the pattern the request was matched with is known,
+// which requires go1.23 and the patterns of go1.22 enabled in http.ServeMux. Otherwise any other path serves the
+// report. Serving the state of the container never constructs any bean.
+package beanshttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/jucardi/go-beans/beans"
+)
+
+// Report is the state of the container served by the handler.
+type Report struct {
+	ActiveProfiles []string     `json:"activeProfiles"`
+	Beans          []Bean       `json:"beans"`
+	Graph          *beans.Graph `json:"graph"`
+}
+
+// Bean describes a registered bean. See beans.BeanDescriptor
+type Bean struct {
+	Type         string     `json:"type"`
+	Name         string     `json:"name"`
+	Primary      bool       `json:"primary"`
+	Scope        string     `json:"scope"`
+	Instantiated bool       `json:"instantiated"`
+	ConcreteType string     `json:"concreteType,omitempty"`
+	Profiles     []string   `json:"profiles,omitempty"`
+	Active       bool       `json:"active"`
+	Overridden   bool       `json:"overridden"`
+	Overrides    []Override `json:"overrides,omitempty"`
+	Timings      Timings    `json:"timings"`
+}
+
+// Override is a registration replaced by the bean.
+type Override struct {
+	Time         time.Time `json:"time"`
+	PreviousType string    `json:"previousType,omitempty"`
+}
+
+// Timings are the construction timings of a bean, in nanoseconds.
+type Timings struct {
+	Constructions int64 `json:"constructions"`
+	LastNanos     int64 `json:"lastNanos"`
+	TotalNanos    int64 `json:"totalNanos"`
+}
+
+type handler struct {
+	c *beans.Container
+}
+
+// Handler returns a read-only http.Handler serving the state of the provided container. If the container is nil, the
+// default container is used.
+func Handler(c *beans.Container) http.Handler {
+	if c == nil {
+		c = beans.Default()
+	}
+	return &handler{c: c}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "the beans endpoint is read-only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch relativePath(r) {
+	case "":
+		writeJSON(w, NewReport(h.c))
+	case "graph":
+		h.serveGraph(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// relativePath returns the path of the request relative to the mount point of the handler, known from the pattern
+// the request was matched with by http.ServeMux since go1.23, unless the patterns prior to go1.22 are kept with the
+// 'httpmuxgo121' setting, which is the default for main modules declaring a go version prior to go1.22.
+//
+// Without a pattern the mount point is unknown, so the last segment of the path selects the resource: 'graph' serves
+// the graph and any other path serves the report. This also covers the mount point being removed with
+// http.StripPrefix.
+func relativePath(r *http.Request) string {
+	p := path.Clean("/" + r.URL.Path)
+	pattern := requestPattern(r)
+	if pattern == "" {
+		if path.Base(p) == "graph" {
+			return "graph"
+		}
+		return ""
+	}
+
+	// The method and the host of the pattern are not part of the path.
+	if i := strings.Index(pattern, "/"); i >= 0 {
+		pattern = pattern[i:]
+	}
+	mount := strings.TrimSuffix(strings.TrimSuffix(pattern, "{$}"), "/")
+	if p == mount || strings.HasPrefix(p, mount+"/") {
+		p = p[len(mount):]
+	}
+	return strings.Trim(p, "/")
+}
+
+func (h *handler) serveGraph(w http.ResponseWriter, r *http.Request) {
+	g := h.c.DependencyGraph()
+	switch format := strings.ToLower(r.URL.Query().Get("format")); format {
+	case "", "json":
+		writeJSON(w, g)
+	case "dot":
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		_, _ = w.Write([]byte(g.DOT()))
+	case "mermaid":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(g.Mermaid()))
+	default:
+		http.Error(w, "unsupported graph format '"+format+"', expected 'json', 'dot' or 'mermaid'", http.StatusBadRequest)
+	}
+}
+
+// NewReport returns the state of the container served by the handler. It never constructs any bean.
+func NewReport(c *beans.Container) *Report {
+	ret := &Report{
+		ActiveProfiles: c.ActiveProfiles(),
+		Beans:          []Bean{},
+		Graph:          c.DependencyGraph(),
+	}
+	for _, d := range c.ListBeans() {
+		b := Bean{
+			Type:         d.Type.String(),
+			Name:         d.Name,
+			Primary:      d.Primary,
+			Scope:        d.Scope.String(),
+			Instantiated: d.Instantiated,
+			Profiles:     d.Profiles,
+			Active:       d.Active,
+			Overridden:   d.Overridden,
+			Timings: Timings{
+				Constructions: d.Constructions,
+				LastNanos:     int64(d.LastConstructionTime),
+				TotalNanos:    int64(d.TotalConstructionTime),
+			},
+		}
+		if d.ConcreteType != nil {
+			b.ConcreteType = d.ConcreteType.String()
+		}
+		for _, o := range d.Overrides {
+			override := Override{Time: o.Time}
+			if o.PreviousType != nil {
+				override.PreviousType = o.PreviousType.String()
+			}
+			b.Overrides = append(b.Overrides, override)
+		}
+		ret.Beans = append(ret.Beans, b)
+	}
+	return ret
+}
+
+func writeJSON(w http.ResponseWriter, val interface{}) {
+	data, err := json.MarshalIndent(val, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
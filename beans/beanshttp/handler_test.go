@@ -0,0 +1,111 @@
+package beanshttp_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	"github.com/jucardi/go-beans/beans/beanshttp"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+type IRepo interface {
+	Find() string
+}
+
+type repo struct {
+	name string
+}
+
+func (r *repo) Find() string {
+	return r.name
+}
+
+func newServer() (*int, *httptest.Server) {
+	c := beans.NewContainer()
+	built := 0
+	ShouldNotError(c.Register((*IRepo)(nil), "sql", &repo{name: "sql"}))
+	ShouldNotError(c.RegisterFunc((*IRepo)(nil), "lazy", func() interface{} {
+		built++
+		return &repo{name: "lazy"}
+	}, true))
+	ShouldNotError(c.SetPrimary((*IRepo)(nil), "sql"))
+	c.SetAllowOverrides(true)
+	ShouldNotError(c.Register((*IRepo)(nil), "sql", &repo{name: "mock"}))
+	c.Resolve((*IRepo)(nil), "sql")
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/beans/", beanshttp.Handler(c))
+	return &built, httptest.NewServer(mux)
+}
+
+func TestHandler(t *testing.T) {
+	Convey("Testing the beans debug endpoint", t, func() {
+		built, server := newServer()
+		defer server.Close()
+
+		Convey("The registered beans are served as JSON without constructing them", t, func() {
+			resp, err := http.Get(server.URL + "/debug/beans/")
+			ShouldNotError(err)
+			defer resp.Body.Close()
+			ShouldEqual(http.StatusOK, resp.StatusCode)
+			ShouldEqual("application/json", resp.Header.Get("Content-Type"))
+
+			report := &beanshttp.Report{}
+			ShouldNotError(json.NewDecoder(resp.Body).Decode(report))
+			ShouldEqual(0, *built)
+			ShouldEqual(2, len(report.Beans))
+			ShouldEqual(2, len(report.Graph.Nodes))
+
+			lazy, sql := report.Beans[0], report.Beans[1]
+			ShouldEqual("beanshttp_test.IRepo", lazy.Type)
+			ShouldEqual("lazy", lazy.Name)
+			ShouldBeFalse(lazy.Instantiated)
+			ShouldEqual("", lazy.ConcreteType)
+
+			ShouldEqual("sql", sql.Name)
+			ShouldBeTrue(sql.Primary)
+			ShouldEqual("singleton", sql.Scope)
+			ShouldBeTrue(sql.Instantiated)
+			ShouldEqual("*beanshttp_test.repo", sql.ConcreteType)
+			ShouldBeTrue(sql.Overridden)
+			ShouldEqual(1, len(sql.Overrides))
+			ShouldEqual(int64(1), sql.Timings.Constructions)
+		})
+		Convey("The dependency graph is served in multiple formats", t, func() {
+			resp, err := http.Get(server.URL + "/debug/beans/graph?format=dot")
+			ShouldNotError(err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			ShouldNotError(err)
+			ShouldEqual(http.StatusOK, resp.StatusCode)
+			ShouldContain(string(body), "digraph beans {")
+
+			resp, err = http.Get(server.URL + "/debug/beans/graph?format=svg")
+			ShouldNotError(err)
+			resp.Body.Close()
+			ShouldEqual(http.StatusBadRequest, resp.StatusCode)
+		})
+		Convey("The resources are found without the pattern of the request", t, func() {
+			for _, p := range []string{"/debug/beans", "/debug/beans/", "/debug/beans/graph"} {
+				resp, err := http.Get(server.URL + p)
+				ShouldNotError(err)
+				resp.Body.Close()
+				ShouldEqual(http.StatusOK, resp.StatusCode)
+			}
+
+			rec := httptest.NewRecorder()
+			http.StripPrefix("/debug/beans", beanshttp.Handler(nil)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/beans/graph", nil))
+			ShouldEqual(http.StatusOK, rec.Code)
+		})
+		Convey("The endpoint is read-only", t, func() {
+			rec := httptest.NewRecorder()
+			beanshttp.Handler(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/beans", nil))
+			ShouldEqual(http.StatusMethodNotAllowed, rec.Code)
+			ShouldEqual("GET, HEAD", rec.Header().Get("Allow"))
+		})
+	})
+}
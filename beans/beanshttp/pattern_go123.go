@@ -0,0 +1,10 @@
+//go:build go1.23
+
+package beanshttp
+
+import "net/http"
+
+// requestPattern returns the pattern the request was matched with by http.ServeMux.
+func requestPattern(r *http.Request) string {
+	return r.Pattern
+}
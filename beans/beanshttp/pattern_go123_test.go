@@ -0,0 +1,32 @@
+//go:build go1.23
+
+package beanshttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	"github.com/jucardi/go-beans/beans/beanshttp"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+func TestHandlerPattern(t *testing.T) {
+	Convey("Testing the beans debug endpoint routes relative to the pattern of the request", t, func() {
+		h := beanshttp.Handler(beans.NewContainer())
+		serve := func(pattern, target string) int {
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			req.Pattern = pattern
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			return rec.Code
+		}
+
+		ShouldEqual(http.StatusOK, serve("/debug/beans/", "/debug/beans/"))
+		ShouldEqual(http.StatusOK, serve("GET /debug/beans/", "/debug/beans/graph"))
+		for _, p := range []string{"/debug/beans/typo", "/debug/beans/typo/graph", "/debug/beans/graph/typo"} {
+			ShouldEqual(http.StatusNotFound, serve("/debug/beans/", p))
+		}
+	})
+}
@@ -0,0 +1,10 @@
+//go:build !go1.23
+
+package beanshttp
+
+import "net/http"
+
+// requestPattern returns an empty pattern, http.Request exposes the matched pattern since go1.23.
+func requestPattern(*http.Request) string {
+	return ""
+}
@@ -0,0 +1,139 @@
+// Package beanstest provides helpers to replace beans of the default container in tests, reverting every change once
+// the test finishes so registrations never leak between tests.
+//
+//	func TestMain(m *testing.M) {
+//	    beanstest.Main(m)
+//	}
+//
+//	func TestSend(t *testing.T) {
+//	    beanstest.Isolate(t)
+//	    beanstest.Override(t, (*IAlertHandler)(nil), "email", &MockAlertHandler{})
+//
+//	    NewNotifier().Notify("hello")
+//	    beanstest.AssertResolved(t, (*IAlertHandler)(nil), "email")
+//	}
+//
+// The helpers operate over the default container (see beans.Default), which is shared by the whole test binary, so
+// tests using them must not run in parallel. Tests that require parallelism should use independent containers instead
+// (see beans.NewContainer).
+package beanstest
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+)
+
+// baseline is the snapshot of the production registrations, see Main
+var baseline *beans.Snapshot
+
+// Main takes the snapshot of the production registrations used by Isolate and runs the tests. It must be invoked from
+// TestMain, so the snapshot is taken once the init functions of the packages under test registered their beans and
+// before any test changes the registry.
+func Main(m *testing.M) {
+	baseline = beans.Default().Snapshot()
+	os.Exit(m.Run())
+}
+
+// Snapshot takes a copy of the registry of the default container, which can be restored with Restore.
+func Snapshot() *beans.Snapshot {
+	return beans.Default().Snapshot()
+}
+
+// Restore reverts the registry of the default container to the provided snapshot.
+func Restore(s *beans.Snapshot) error {
+	return beans.Default().Restore(s)
+}
+
+// Override registers the mock as the bean by the given name, replacing the current registration if any, regardless
+// of the Allow Overrides setting. The whole registry is reverted when the test finishes, including any other
+// registration made by the test after the override.
+func Override(t testing.TB, ref interface{}, name string, mock interface{}, opts ...beans.Option) {
+	t.Helper()
+	s := Snapshot()
+	c := beans.Default()
+
+	allow := c.AllowOverrides()
+	c.SetAllowOverrides(true)
+	err := c.Register(ref, name, mock, opts...)
+	c.SetAllowOverrides(allow)
+	if err != nil {
+		t.Fatalf("unable to override the bean %s: %v", beanKey(ref, name), err)
+	}
+	t.Cleanup(func() { restore(t, s) })
+}
+
+// Isolate resets the registry of the default container to the production registrations for the duration of the test,
+// with none of the singletons instantiated, and reverts it to its previous state when the test finishes. The
+// production registrations are the ones captured by Main before the tests run, the test fails if Main was not invoked
+// from TestMain.
+func Isolate(t testing.TB) {
+	t.Helper()
+	if baseline == nil {
+		t.Fatal("the production registrations were not captured, invoke beanstest.Main from TestMain")
+	}
+	s := Snapshot()
+	if err := Restore(baseline.WithoutInstances()); err != nil {
+		t.Fatalf("unable to isolate the beans registry: %v", err)
+	}
+	t.Cleanup(func() { restore(t, s) })
+}
+
+// AssertResolved fails the test if the bean by the given name was never resolved. An empty name refers to the primary
+// bean of the type. Resolutions are counted since the bean was registered, overridden (see Override) or isolated (see
+// Isolate).
+func AssertResolved(t testing.TB, ref interface{}, name string) {
+	t.Helper()
+	if b, ok := describe(t, ref, name); ok && b.Resolutions == 0 {
+		t.Errorf("expected the bean %s to be resolved, but it was never resolved", beanKey(ref, b.Name))
+	}
+}
+
+// AssertNotResolved fails the test if the bean by the given name was resolved. An empty name refers to the primary
+// bean of the type. See AssertResolved
+func AssertNotResolved(t testing.TB, ref interface{}, name string) {
+	t.Helper()
+	if b, ok := describe(t, ref, name); ok && b.Resolutions > 0 {
+		t.Errorf("expected the bean %s not to be resolved, but it was resolved %d time(s)", beanKey(ref, b.Name), b.Resolutions)
+	}
+}
+
+// AssertResolvedTimes fails the test if the bean by the given name was not resolved exactly the expected number of
+// times. An empty name refers to the primary bean of the type. See AssertResolved
+func AssertResolvedTimes(t testing.TB, ref interface{}, name string, expected int) {
+	t.Helper()
+	if b, ok := describe(t, ref, name); ok && b.Resolutions != int64(expected) {
+		t.Errorf("expected the bean %s to be resolved %d time(s), but it was resolved %d time(s)", beanKey(ref, b.Name), expected, b.Resolutions)
+	}
+}
+
+func restore(t testing.TB, s *beans.Snapshot) {
+	if err := Restore(s); err != nil {
+		t.Errorf("unable to restore the beans registry: %v", err)
+	}
+}
+
+// describe returns the descriptor of the bean by the given name, or of the primary bean if the name is empty. The test
+// fails if the bean is not registered.
+func describe(t testing.TB, ref interface{}, name string) (beans.BeanDescriptor, bool) {
+	t.Helper()
+	typ := reflect.TypeOf(ref).Elem()
+	for _, b := range beans.ListBeans() {
+		if b.Type == typ && ((name == "" && b.Primary) || (name != "" && b.Name == name)) {
+			return b, true
+		}
+	}
+	if name == "" {
+		t.Errorf("no primary bean found for type '%s'", typ)
+	} else {
+		t.Errorf("the bean %s is not registered", beanKey(ref, name))
+	}
+	return beans.BeanDescriptor{}, false
+}
+
+func beanKey(ref interface{}, name string) string {
+	return fmt.Sprintf("'%s' of type '%s'", name, reflect.TypeOf(ref).Elem())
+}
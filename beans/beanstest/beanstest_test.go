@@ -0,0 +1,130 @@
+package beanstest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	"github.com/jucardi/go-beans/beans/beanstest"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+type IRepo interface {
+	Find() string
+}
+
+type repo struct {
+	name string
+}
+
+func (r *repo) Find() string {
+	return r.name
+}
+
+type service struct {
+	repo IRepo
+}
+
+var repoRef = (*IRepo)(nil)
+
+// The production registrations.
+func init() {
+	if err := beans.Register(repoRef, "sql", &repo{name: "sql"}); err != nil {
+		panic(err)
+	}
+	if err := beans.RegisterConstructor((*fmt.Stringer)(nil), "service", func(r IRepo) *service {
+		return &service{repo: r}
+	}, beans.Singleton()); err != nil {
+		panic(err)
+	}
+}
+
+func TestMain(m *testing.M) {
+	beanstest.Main(m)
+}
+
+func (s *service) String() string {
+	return s.repo.Find()
+}
+
+// recorder records the failures reported by the assertions.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func serviceName() string {
+	return beans.Resolve((*fmt.Stringer)(nil), "service").(fmt.Stringer).String()
+}
+
+func TestOverride(t *testing.T) {
+	Convey("Testing the overrides reverted when the test finishes", t, func() {
+		t.Run("override", func(t *testing.T) {
+			beanstest.Isolate(t)
+			beanstest.Override(t, repoRef, "sql", &repo{name: "mock"})
+			ShouldEqual("mock", serviceName())
+			ShouldError(beans.Register(repoRef, "sql", &repo{}))
+		})
+		ShouldEqual("sql", serviceName())
+		ShouldBeFalse(beans.Default().AllowOverrides())
+
+		t.Run("mocks registered by a new name", func(t *testing.T) {
+			beanstest.Override(t, repoRef, "memory", &repo{name: "memory"})
+			ShouldBeTrue(beans.Exists(repoRef, "memory"))
+		})
+		ShouldBeFalse(beans.Exists(repoRef, "memory"))
+	})
+}
+
+func TestIsolate(t *testing.T) {
+	Convey("Testing the isolated registries", t, func() {
+		t.Run("isolated", func(t *testing.T) {
+			beanstest.Isolate(t)
+			ShouldEqual("sql", serviceName())
+			ShouldNotError(beans.Register(repoRef, "memory", &repo{name: "memory"}))
+			ShouldBeTrue(beans.Exists(repoRef, "memory"))
+		})
+		ShouldBeFalse(beans.Exists(repoRef, "memory"))
+
+		s := beanstest.Snapshot()
+		defer func() { ShouldNotError(beanstest.Restore(s)) }()
+		beans.SetAllowOverrides(true)
+		ShouldNotError(beans.Register(repoRef, "sql", &repo{name: "leaked"}))
+
+		t.Run("seeded from the production registrations", func(t *testing.T) {
+			beanstest.Isolate(t)
+			ShouldEqual("sql", serviceName())
+			beanstest.AssertResolvedTimes(t, repoRef, "sql", 1)
+		})
+		ShouldEqual("leaked", beans.Resolve(repoRef, "sql").(IRepo).Find())
+	})
+}
+
+func TestAssertions(t *testing.T) {
+	Convey("Testing the resolution assertions", t, func() {
+		t.Run("assertions", func(t *testing.T) {
+			beanstest.Isolate(t)
+			r := &recorder{TB: t}
+			beanstest.AssertNotResolved(r, repoRef, "sql")
+			beanstest.AssertResolved(r, repoRef, "")
+			ShouldEqual(1, len(r.failures))
+			ShouldContain(r.failures[0], "to be resolved, but it was never resolved")
+
+			serviceName()
+			r.failures = nil
+			beanstest.AssertResolved(r, repoRef, "sql")
+			beanstest.AssertResolvedTimes(r, repoRef, "", 1)
+			beanstest.AssertNotResolved(r, repoRef, "sql")
+			beanstest.AssertResolved(r, repoRef, "missing")
+			ShouldEqual(2, len(r.failures))
+			ShouldContain(r.failures[0], "not to be resolved, but it was resolved 1 time(s)")
+			ShouldContain(r.failures[1], "is not registered")
+		})
+	})
+}
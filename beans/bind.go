@@ -0,0 +1,266 @@
+package beans
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	configTag  = "config"
+	defaultTag = "default"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Bind fills the fields of the target struct tagged with `config:"..."` from the properties of the container. See
+// beans.Bind
+func (c *Container) Bind(prefix string, target interface{}) error {
+	if err := validateBindTarget(target); err != nil {
+		return err
+	}
+	var errs []error
+	c.bindStruct(prefix, reflect.ValueOf(target).Elem(), &errs)
+	return newMultiError(fmt.Sprintf("unable to bind %d property(ies) into '%T'", len(errs), target), errs)
+}
+
+// RegisterConfig registers the target config struct as a singleton bean of its pointer type, which is bound with the
+// properties under the provided prefix when it is constructed. See beans.RegisterConfig
+func (c *Container) RegisterConfig(name, prefix string, target interface{}, opts ...Option) error {
+	if err := validateBindTarget(target); err != nil {
+		return err
+	}
+	info := &constructorInfo{
+		ctor: func(context.Context) (interface{}, error) {
+			return target, c.Bind(prefix, target)
+		},
+		scope: ScopeSingleton,
+	}
+	info.setConcreteType(reflect.TypeOf(target))
+	newBeanOptions(opts...).apply(info)
+	return c.register(reflect.TypeOf(target), name, info)
+}
+
+func validateBindTarget(target interface{}) error {
+	v := reflect.ValueOf(target)
+	if !v.IsValid() || v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("the bind target must be a non nil pointer to a struct, '%T' provided", target)
+	}
+	return nil
+}
+
+func (c *Container) bindStruct(prefix string, v reflect.Value, errs *[]error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup(configTag)
+		if !ok || tag == "-" {
+			continue
+		}
+		if !field.IsExported() {
+			*errs = append(*errs, fmt.Errorf("the field '%s' is tagged for binding but it is not exported", field.Name))
+			continue
+		}
+
+		var def *string
+		if val, ok := field.Tag.Lookup(defaultTag); ok {
+			def = &val
+		}
+		c.bindValue(joinKey(prefix, tag), v.Field(i), def, errs)
+	}
+}
+
+// bindValue sets the value of the property by the given key into 'v'. Structs are bound field by field, while lists
+// and maps are taken either from a single property with comma separated values, or from the properties nested under
+// the key, such as 'servers.0' or 'headers.token'. If the property is not found the default value is used, if any.
+func (c *Container) bindValue(key string, v reflect.Value, def *string, errs *[]error) {
+	t := v.Type()
+	if t.Kind() == reflect.Struct && !reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		c.bindStruct(key, v, errs)
+		return
+	}
+	if val, ok := c.Property(key); ok {
+		c.setValue(key, v, val, errs)
+		return
+	}
+	if (t.Kind() == reflect.Slice || t.Kind() == reflect.Map) && c.bindNested(key, v, errs) {
+		return
+	}
+	if def != nil {
+		c.setValue(key, v, *def, errs)
+	}
+}
+
+// bindNested binds a list or a map from the properties nested under the key. Returns false if there are none.
+func (c *Container) bindNested(key string, v reflect.Value, errs *[]error) bool {
+	var segments []string
+	seen := map[string]bool{}
+	for _, k := range c.propertyKeys(key) {
+		if seg := strings.SplitN(k, ".", 2)[0]; !seen[seg] {
+			seen[seg] = true
+			segments = append(segments, seg)
+		}
+	}
+	if len(segments) == 0 {
+		return false
+	}
+
+	t := v.Type()
+	if t.Kind() == reflect.Slice {
+		var indexes []int
+		for _, seg := range segments {
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 {
+				continue
+			}
+			// Indexes written differently, such as '00' or '+1', would bind the same element more than once.
+			if strconv.Itoa(i) != seg {
+				*errs = append(*errs, fmt.Errorf("unable to bind the list '%s': invalid index '%s', expected '%d'", key, seg, i))
+				return true
+			}
+			indexes = append(indexes, i)
+		}
+		if len(indexes) == 0 {
+			return false
+		}
+		sort.Ints(indexes)
+		// The indexes must be contiguous, so a single property with a large index cannot allocate a huge list.
+		if last := indexes[len(indexes)-1]; last >= len(indexes) {
+			*errs = append(*errs, fmt.Errorf("unable to bind the list '%s': the index %d is out of range, the indexes of the %d element(s) must be contiguous starting at 0", key, last, len(indexes)))
+			return true
+		}
+		list := reflect.MakeSlice(t, len(indexes), len(indexes))
+		for _, i := range indexes {
+			c.bindValue(joinKey(key, strconv.Itoa(i)), list.Index(i), nil, errs)
+		}
+		v.Set(list)
+		return true
+	}
+
+	m := reflect.MakeMapWithSize(t, len(segments))
+	for _, seg := range segments {
+		k, err := convertProperty(seg, t.Key())
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("unable to bind the key '%s' of the property '%s' to %s: %w", seg, key, t.Key(), err))
+			continue
+		}
+		elem := reflect.New(t.Elem()).Elem()
+		c.bindValue(joinKey(key, seg), elem, nil, errs)
+		m.SetMapIndex(k, elem)
+	}
+	v.Set(m)
+	return true
+}
+
+func (c *Container) setValue(key string, v reflect.Value, raw string, errs *[]error) {
+	val, err := convertProperty(raw, v.Type())
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("unable to bind the property '%s' with value '%s' to %s: %w", key, raw, v.Type(), err))
+		return
+	}
+	v.Set(val)
+}
+
+// convertProperty converts the raw value of a property to the provided type. Lists are provided as comma separated
+// values, eg: 'a,b,c', and maps as comma separated pairs, eg: 'a=1,b=2'
+func convertProperty(raw string, t reflect.Type) (reflect.Value, error) {
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		ptr := reflect.New(t)
+		err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+		return ptr.Elem(), err
+	}
+
+	ret := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		ret.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return ret, err
+		}
+		ret.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if t == durationType {
+			d, err := time.ParseDuration(raw)
+			if err != nil {
+				return ret, err
+			}
+			ret.SetInt(int64(d))
+			break
+		}
+		i, err := strconv.ParseInt(raw, 10, t.Bits())
+		if err != nil {
+			return ret, err
+		}
+		ret.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 10, t.Bits())
+		if err != nil {
+			return ret, err
+		}
+		ret.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, t.Bits())
+		if err != nil {
+			return ret, err
+		}
+		ret.SetFloat(f)
+	case reflect.Slice:
+		parts := splitList(raw)
+		ret = reflect.MakeSlice(t, 0, len(parts))
+		for _, part := range parts {
+			elem, err := convertProperty(part, t.Elem())
+			if err != nil {
+				return ret, err
+			}
+			ret = reflect.Append(ret, elem)
+		}
+	case reflect.Map:
+		ret = reflect.MakeMap(t)
+		for _, part := range splitList(raw) {
+			pair := strings.SplitN(part, "=", 2)
+			if len(pair) != 2 {
+				return ret, fmt.Errorf("invalid map entry '%s', expected 'key=value'", part)
+			}
+			k, err := convertProperty(strings.TrimSpace(pair[0]), t.Key())
+			if err != nil {
+				return ret, err
+			}
+			elem, err := convertProperty(strings.TrimSpace(pair[1]), t.Elem())
+			if err != nil {
+				return ret, err
+			}
+			ret.SetMapIndex(k, elem)
+		}
+	default:
+		return ret, fmt.Errorf("unsupported type")
+	}
+	return ret, nil
+}
+
+func splitList(raw string) []string {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,122 @@
+package beans
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+type orderedBean struct {
+	name     string
+	instance interface{}
+	order    int
+	seq      uint64
+}
+
+// GetAll gets all the instances registered for the provided type, sorted by their priority. See beans.ResolveAll
+func (c *Container) GetAll(t reflect.Type) ([]interface{}, error) {
+	return c.GetAllCtx(context.Background(), t)
+}
+
+// GetAllCtx gets all the instances registered for the provided type, sorted by their priority, using the scope of the
+// provided context to resolve context scoped beans. See beans.ResolveAllCtx
+func (c *Container) GetAllCtx(ctx context.Context, t reflect.Type) ([]interface{}, error) {
+	all, err := c.getAll(ctx, t)
+	ret := make([]interface{}, 0, len(all))
+	for _, b := range all {
+		ret = append(ret, b.instance)
+	}
+	return ret, err
+}
+
+// GetMap gets all the instances registered for the provided type, indexed by their bean names. See beans.ResolveMap
+func (c *Container) GetMap(t reflect.Type) (map[string]interface{}, error) {
+	return c.GetMapCtx(context.Background(), t)
+}
+
+// GetMapCtx gets all the instances registered for the provided type, indexed by their bean names, using the scope of
+// the provided context to resolve context scoped beans. See beans.ResolveMapCtx
+func (c *Container) GetMapCtx(ctx context.Context, t reflect.Type) (map[string]interface{}, error) {
+	all, err := c.getAll(ctx, t)
+	ret := make(map[string]interface{}, len(all))
+	for _, b := range all {
+		ret[b.name] = b.instance
+	}
+	return ret, err
+}
+
+// ResolveAll resolves all the beans registered for the type, sorted by their priority. See beans.ResolveAll
+func (c *Container) ResolveAll(ref interface{}) ([]interface{}, error) {
+	return c.GetAll(getType(ref))
+}
+
+// ResolveMap resolves all the beans registered for the type, indexed by their bean names. See beans.ResolveMap
+func (c *Container) ResolveMap(ref interface{}) (map[string]interface{}, error) {
+	return c.GetMap(getType(ref))
+}
+
+// ResolveAllCtx resolves all the beans registered for the type, sorted by their priority, using the scope of the
+// provided context to resolve context scoped beans. See beans.ResolveAllCtx
+func (c *Container) ResolveAllCtx(ctx context.Context, ref interface{}) ([]interface{}, error) {
+	return c.GetAllCtx(ctx, getType(ref))
+}
+
+// ResolveMapCtx resolves all the beans registered for the type, indexed by their bean names, using the scope of the
+// provided context to resolve context scoped beans. See beans.ResolveMapCtx
+func (c *Container) ResolveMapCtx(ctx context.Context, ref interface{}) (map[string]interface{}, error) {
+	return c.GetMapCtx(ctx, getType(ref))
+}
+
+func (c *Container) getAll(ctx context.Context, t reflect.Type) ([]*orderedBean, error) {
+	active := c.activeProfiles()
+	c.mux.RLock()
+	var names []string
+	ctors := map[string]*constructorInfo{}
+	if dep, ok := c.dependencies[t]; ok {
+		for name, ctor := range dep.ctors {
+			if !ctor.isActive(active) {
+				continue
+			}
+			names = append(names, name)
+			ctors[name] = ctor
+		}
+	}
+	c.mux.RUnlock()
+	// The beans are constructed in the order they were registered, so the side effects of their constructors and the
+	// aggregated errors do not depend on the iteration order of the map.
+	sort.Slice(names, func(i, j int) bool { return ctors[names[i]].seq < ctors[names[j]].seq })
+
+	var ret []*orderedBean
+	var errs []error
+	for _, name := range names {
+		instance, _, err := c.instanceOf(ctx, t, name)
+		if err != nil {
+			errs = append(errs, c.resolveFailed(t, name, err))
+			continue
+		}
+		ctors[name].resolutions.Add(1)
+
+		b := &orderedBean{
+			name:     name,
+			instance: triggerOnResolve(instance),
+			order:    math.MaxInt,
+			seq:      ctors[name].seq,
+		}
+		if p := ctors[name].priority; p != nil {
+			b.order = *p
+		} else if o, ok := b.instance.(IOrdered); ok {
+			b.order = o.Order()
+		}
+		ret = append(ret, b)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].order != ret[j].order {
+			return ret[i].order < ret[j].order
+		}
+		return ret[i].seq < ret[j].seq
+	})
+	return ret, newMultiError(fmt.Sprintf("unable to resolve %d bean(s) of type '%s'", len(errs), t.Name()), errs)
+}
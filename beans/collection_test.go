@@ -0,0 +1,107 @@
+package beans_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+type orderedOther struct {
+	OtherImpl1
+	order int
+}
+
+func (o *orderedOther) Order() int {
+	return o.order
+}
+
+func names(list []interface{}) []string {
+	var ret []string
+	for _, v := range list {
+		ret = append(ret, v.(IOther).Name())
+	}
+	return ret
+}
+
+func TestResolveAll(t *testing.T) {
+	Convey("Testing ResolveAll and ResolveMap", t, func() {
+		Convey("Ordered by priority, order and registration", t, func() {
+			c := beans.NewContainer()
+			ShouldNotError(c.Register((*IOther)(nil), "unordered1", &OtherImpl1{name: "unordered1"}))
+			ShouldNotError(c.Register((*IOther)(nil), "ordered", &orderedOther{OtherImpl1: OtherImpl1{name: "ordered"}, order: 5}))
+			ShouldNotError(c.Register((*IOther)(nil), "unordered2", &OtherImpl1{name: "unordered2"}))
+			ShouldNotError(c.Register((*IOther)(nil), "priority", &orderedOther{OtherImpl1: OtherImpl1{name: "priority"}, order: 10}, beans.Priority(1)))
+			ShouldNotError(c.RegisterConstructor((*IOther)(nil), "ctor", func() IOther {
+				return &OtherImpl1{name: "ctor"}
+			}, beans.Priority(5)))
+
+			for i := 0; i < 5; i++ {
+				all, err := c.ResolveAll((*IOther)(nil))
+				ShouldNotError(err)
+				ShouldEqual([]string{"priority", "ordered", "ctor", "unordered1", "unordered2"}, names(all))
+			}
+
+			m, err := c.ResolveMap((*IOther)(nil))
+			ShouldNotError(err)
+			ShouldEqual(5, len(m))
+			ShouldEqual("ordered", m["ordered"].(IOther).Name())
+		})
+		Convey("Failures are aggregated", t, func() {
+			c := beans.NewContainer()
+			ShouldNotError(c.Register((*IOther)(nil), "working", &OtherImpl1{name: "working"}))
+			ShouldNotError(c.RegisterFunc((*IOther)(nil), "nil", func() interface{} { return nil }))
+
+			all, err := c.ResolveAll((*IOther)(nil))
+			ShouldBeTrue(errors.Is(err, beans.ErrNilInstance))
+			ShouldEqual([]string{"working"}, names(all))
+		})
+		Convey("Beans are constructed in registration order", t, func() {
+			c := beans.NewContainer()
+			var built []string
+			for _, name := range []string{"c", "a", "d", "b"} {
+				name := name
+				ShouldNotError(c.RegisterFunc((*IOther)(nil), name, func() interface{} {
+					built = append(built, name)
+					return nil
+				}))
+			}
+
+			_, err := c.ResolveAll((*IOther)(nil))
+			var multi *beans.MultiError
+			ShouldBeTrue(errors.As(err, &multi))
+			ShouldEqual([]string{"c", "a", "d", "b"}, built)
+			for i, name := range built {
+				ShouldContain(multi.Errors[i].Error(), "IOther/"+name)
+			}
+		})
+		Convey("Context scoped beans are resolved with the scope of the context", t, func() {
+			c := beans.NewContainer()
+			ShouldNotError(c.Register((*IOther)(nil), "singleton", &OtherImpl1{name: "singleton"}))
+			ShouldNotError(c.RegisterConstructor((*IOther)(nil), "scoped", func() IOther {
+				return &OtherImpl1{name: "scoped"}
+			}, beans.Scoped()))
+
+			_, err := c.ResolveAll((*IOther)(nil))
+			ShouldBeTrue(errors.Is(err, beans.ErrNoScope))
+
+			ctx, cancel := beans.NewScope(context.Background())
+			defer cancel()
+			all, err := c.ResolveAllCtx(ctx, (*IOther)(nil))
+			ShouldNotError(err)
+			ShouldEqual([]string{"singleton", "scoped"}, names(all))
+
+			m, err := c.ResolveMapCtx(ctx, (*IOther)(nil))
+			ShouldNotError(err)
+			ShouldEqual(all[1], m["scoped"])
+		})
+		Convey("Type not registered", t, func() {
+			before()
+			all, err := beans.ResolveAll((*IOther)(nil))
+			ShouldNotError(err)
+			ShouldEqual(0, len(all))
+		})
+	})
+}
@@ -0,0 +1,164 @@
+package beans_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+const parallelWorkers = 64
+
+func runParallel(workers int, fn func(i int)) {
+	wg := sync.WaitGroup{}
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			fn(i)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+}
+
+func TestConcurrentLazySingleton(t *testing.T) {
+	Convey("Testing concurrent resolution of a lazy singleton", t, func() {
+		before()
+		var built int32
+		ShouldNotError(beans.RegisterFunc((*IOther)(nil), "lazy", func() interface{} {
+			atomic.AddInt32(&built, 1)
+			return &OtherImpl1{name: "lazy"}
+		}, true))
+
+		results := make([]IOther, parallelWorkers)
+		runParallel(parallelWorkers, func(i int) {
+			results[i] = beans.Resolve((*IOther)(nil), "lazy").(IOther)
+		})
+
+		ShouldEqual(int32(1), atomic.LoadInt32(&built))
+		for _, r := range results {
+			ShouldEqual(results[0], r)
+		}
+	})
+}
+
+func TestConcurrentFirstTimeResolve(t *testing.T) {
+	Convey("Testing OnFirstTimeResolve is triggered once under concurrent resolution", t, func() {
+		var first int32
+		beans.SetAllowOverrides(true)
+		ShouldNotError(beans.Clear())
+		beans.SetAllowOverrides(false)
+		ShouldNotError(beans.Register(ComponentType, "counted", &countingService{first: &first}))
+
+		runParallel(parallelWorkers, func(int) {
+			beans.Resolve(ComponentType, "counted")
+		})
+
+		ShouldEqual(int32(1), atomic.LoadInt32(&first))
+	})
+}
+
+func TestConcurrentRegisterAndResolve(t *testing.T) {
+	Convey("Testing concurrent registrations, overrides and resolutions", t, func() {
+		before()
+		beans.SetAllowOverrides(true)
+		defer beans.SetAllowOverrides(false)
+
+		ref := (*IOther)(nil)
+		runParallel(parallelWorkers, func(i int) {
+			name := "bean-" + strconv.Itoa(i%8)
+			switch i % 4 {
+			case 0:
+				_ = beans.RegisterFunc(ref, name, func() interface{} {
+					return &OtherImpl1{name: name}
+				}, true)
+			case 1:
+				_ = beans.SetPrimary(ref, name, true)
+			case 2:
+				beans.Resolve(ref, name)
+				beans.Primary(ref)
+			case 3:
+				beans.Exists(ref, name)
+				beans.GetPrimaryName(ref)
+				beans.InitComponents()
+			}
+		})
+
+		for i := 0; i < 8; i++ {
+			name := "bean-" + strconv.Itoa(i)
+			if beans.Exists(ref, name) {
+				ShouldEqual(name, beans.Resolve(ref, name).(IOther).Name())
+			}
+		}
+	})
+}
+
+func TestConcurrentCircularDependency(t *testing.T) {
+	Convey("Testing circular dependencies between singletons resolved concurrently", t, func() {
+		before()
+		// Both constructors wait for each other to start, so each goroutine holds one of the beans when resolving the
+		// other one.
+		var arrived int32
+		ready := make(chan struct{})
+		start := func() {
+			if atomic.AddInt32(&arrived, 1) == 2 {
+				close(ready)
+			}
+			<-ready
+		}
+		ShouldNotError(beans.RegisterConstructor(ComponentType, "a", func(ctx context.Context) (IService, error) {
+			start()
+			other, err := beans.ResolveCtxE(ctx, (*IOther)(nil), "b")
+			if err != nil {
+				return nil, err
+			}
+			return &composedService{other: other.(IOther)}, nil
+		}, beans.Singleton()))
+		ShouldNotError(beans.RegisterConstructor((*IOther)(nil), "b", func(ctx context.Context) (IOther, error) {
+			start()
+			if _, err := beans.ResolveCtxE(ctx, ComponentType, "a"); err != nil {
+				return nil, err
+			}
+			return &OtherImpl1{name: "b"}, nil
+		}, beans.Singleton()))
+
+		errs := make([]error, 2)
+		done := make(chan struct{})
+		go func() {
+			runParallel(2, func(i int) {
+				if i == 0 {
+					_, errs[i] = beans.ResolveE(ComponentType, "a")
+				} else {
+					_, errs[i] = beans.ResolveE((*IOther)(nil), "b")
+				}
+			})
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the concurrent resolution of the circular dependency did not finish")
+		}
+		ShouldBeTrue(errors.Is(errs[0], beans.ErrCircularDependency))
+		ShouldBeTrue(errors.Is(errs[1], beans.ErrCircularDependency))
+	})
+}
+
+type countingService struct {
+	TestServiceImpl2
+	first *int32
+}
+
+func (c *countingService) OnFirstTimeResolve() {
+	atomic.AddInt32(c.first, 1)
+}
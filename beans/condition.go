@@ -0,0 +1,142 @@
+package beans
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Condition decides whether a conditional registration is applied. Conditions are evaluated when the container is
+// finalized (see beans.Finalize), once all the unconditional registrations are done.
+type Condition func(c *Container) bool
+
+// pendingBean is a conditional registration waiting for the container to be finalized.
+type pendingBean struct {
+	dependency
+	info *constructorInfo
+}
+
+// Conditional registers the bean only if the provided condition is met when the container is finalized. If multiple
+// conditions are provided, all of them must be met.
+//
+//   Eg.   beans.Register((*ICache)(nil), "memory", NewMemoryCache(), beans.Conditional(func(c *beans.Container) bool {
+//             return !c.Exists((*IRedis)(nil), "default")
+//         }))
+//
+func Conditional(condition Condition) Option {
+	return func(o *beanOptions) {
+		o.conditions = append(o.conditions, condition)
+	}
+}
+
+// ConditionalOnBean registers the bean only if a bean of the referenced type exists when the container is finalized.
+// If names are provided, all the named beans must exist.
+func ConditionalOnBean(ref interface{}, names ...string) Option {
+	t := getType(ref)
+	return Conditional(func(c *Container) bool {
+		return c.matchingBeans(t, names) == max(len(names), 1)
+	})
+}
+
+// ConditionalOnMissingBean registers the bean only if no bean of the referenced type exists when the container is
+// finalized. If names are provided, none of the named beans must exist. It allows library packages to provide default
+// implementations that applications replace by simply registering their own bean, without allowing overrides.
+//
+// Conditions on missing beans are evaluated after all the other conditional registrations, in the order they were
+// registered, so only the first default registered for a type is applied.
+//
+//   Eg.   beans.Register((*ILogger)(nil), "default", &StdLogger{}, beans.ConditionalOnMissingBean((*ILogger)(nil)))
+//
+func ConditionalOnMissingBean(ref interface{}, names ...string) Option {
+	t := getType(ref)
+	return func(o *beanOptions) {
+		o.onMissingBean = true
+		o.conditions = append(o.conditions, func(c *Container) bool {
+			return c.matchingBeans(t, names) == 0
+		})
+	}
+}
+
+// ConditionalOnProperty registers the bean only if the property by the given key has the provided value when the
+// container is finalized. If the value is empty, the property must be set to any value other than 'false'. See
+// beans.Property
+//
+//   Eg.   beans.Register((*IAlertHandler)(nil), "sms", &SmsHandler{}, beans.ConditionalOnProperty("alerts.sms.enabled", "true"))
+//
+func ConditionalOnProperty(key, value string) Option {
+	return Conditional(func(c *Container) bool {
+		actual, ok := c.Property(key)
+		if !ok {
+			return false
+		}
+		if value == "" {
+			return !strings.EqualFold(actual, "false")
+		}
+		return actual == value
+	})
+}
+
+// Finalize evaluates the conditions of the conditional registrations, registering the beans which conditions are met.
+// See beans.Finalize
+func (c *Container) Finalize() error {
+	errs := c.finalize()
+	return newMultiError(fmt.Sprintf("unable to register %d conditional bean(s)", len(errs)), errs)
+}
+
+func (c *Container) finalize() []error {
+	c.mux.Lock()
+	pending := c.pending
+	c.pending = nil
+	c.finalized = true
+	c.mux.Unlock()
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		return !pending[i].info.onMissingBean && pending[j].info.onMissingBean
+	})
+
+	var errs []error
+	for _, p := range pending {
+		if err := c.registerIfMatches(p.t, p.name, p.info); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// registerIfMatches registers the bean if all its conditions are met.
+func (c *Container) registerIfMatches(t reflect.Type, name string, info *constructorInfo) error {
+	for _, condition := range info.conditions {
+		if !condition(c) {
+			c.log().Debug(fmt.Sprintf("conditions not met for type=%s, name=%s, skipping registration", t.String(), name))
+			return nil
+		}
+	}
+	return c.add(t, name, info)
+}
+
+// matchingBeans returns how many of the named beans of the provided type exist and are active. If no names are
+// provided, it returns whether any bean of the type exists.
+func (c *Container) matchingBeans(t reflect.Type, names []string) int {
+	if len(names) > 0 {
+		count := 0
+		for _, name := range names {
+			if c.ExistsByType(t, name) {
+				count++
+			}
+		}
+		return count
+	}
+
+	active := c.activeProfiles()
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	if dep, ok := c.dependencies[t]; ok {
+		for _, ctor := range dep.ctors {
+			if ctor.isActive(active) {
+				return 1
+			}
+		}
+	}
+	return 0
+}
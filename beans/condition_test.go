@@ -0,0 +1,72 @@
+package beans_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+func TestConditionalRegistration(t *testing.T) {
+	Convey("Testing conditional registrations", t, func() {
+		ref := (*IOther)(nil)
+
+		Convey("A default is replaced by a bean registered later", t, func() {
+			c := beans.NewContainer()
+			ShouldNotError(c.Register(ref, "default", &OtherImpl1{name: "default"}, beans.ConditionalOnMissingBean(ref)))
+			ShouldBeFalse(c.Exists(ref, "default"))
+			ShouldNotError(c.Register(ref, "custom", &OtherImpl1{name: "custom"}))
+
+			ShouldNotError(c.Finalize())
+			ShouldBeFalse(c.Exists(ref, "default"))
+			ShouldEqual("custom", c.Primary(ref).(IOther).Name())
+		})
+		Convey("Only the first default of a type is registered", t, func() {
+			c := beans.NewContainer()
+			ShouldNotError(c.Register(ref, "first", &OtherImpl1{name: "first"}, beans.ConditionalOnMissingBean(ref)))
+			ShouldNotError(c.Register(ref, "second", &OtherImpl1{name: "second"}, beans.ConditionalOnMissingBean(ref)))
+			ShouldNotError(c.InitComponents())
+			ShouldEqual("first", c.Primary(ref).(IOther).Name())
+			ShouldBeFalse(c.Exists(ref, "second"))
+		})
+		Convey("Missing bean conditions are evaluated after the other conditions", t, func() {
+			c := beans.NewContainer()
+			ShouldNotError(c.Register(ref, "default", &OtherImpl1{name: "default"}, beans.ConditionalOnMissingBean(ref, "sms")))
+			ShouldNotError(c.Register(ref, "sms", &OtherImpl1{name: "sms"}, beans.ConditionalOnProperty("alerts.sms", "on")))
+			c.SetProperty("alerts.sms", "on")
+			ShouldNotError(c.Finalize())
+			ShouldBeTrue(c.Exists(ref, "sms"))
+			ShouldBeFalse(c.Exists(ref, "default"))
+		})
+		Convey("Beans depending on other beans and custom conditions", t, func() {
+			c := beans.NewContainer()
+			ShouldNotError(c.Register(ComponentType, "svc", &TestServiceImpl2{}, beans.ConditionalOnBean(ref, "custom")))
+			ShouldNotError(c.Register(ref, "custom", &OtherImpl1{name: "custom"}, beans.Conditional(func(c *beans.Container) bool {
+				return !c.Exists(ref, "custom")
+			})))
+			ShouldNotError(c.Finalize())
+			ShouldBeTrue(c.Exists(ref, "custom"))
+			ShouldBeFalse(c.Exists(ComponentType, "svc"))
+
+			ShouldNotError(c.Register(ComponentType, "svc", &TestServiceImpl2{}, beans.ConditionalOnBean(ref)))
+			ShouldBeTrue(c.Exists(ComponentType, "svc"))
+		})
+		Convey("Property conditions read the environment", t, func() {
+			t.Setenv("FEATURE_CACHE", "false")
+			c := beans.NewContainer()
+			ShouldNotError(c.Register(ref, "cache", &OtherImpl1{name: "cache"}, beans.ConditionalOnProperty("feature.cache", "")))
+			ShouldNotError(c.Finalize())
+			ShouldBeFalse(c.Exists(ref, "cache"))
+		})
+		Convey("Registering an existing name fails when finalizing", t, func() {
+			c := beans.NewContainer()
+			ShouldNotError(c.Register(ref, "name", &OtherImpl1{name: "a"}))
+			ShouldNotError(c.Register(ref, "name", &OtherImpl1{name: "b"}, beans.ConditionalOnBean(ref)))
+			err := c.InitComponents()
+			var multi *beans.MultiError
+			ShouldBeTrue(errors.As(err, &multi))
+			ShouldEqual(1, len(multi.Errors))
+		})
+	})
+}
@@ -0,0 +1,108 @@
+package beans
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// dependency describes a bean a constructor depends on. An empty name refers to the primary bean of the type.
+type dependency struct {
+	t    reflect.Type
+	name string
+}
+
+// newInjectedConstructor validates the provided constructor function and returns a function that invokes it, resolving
+// each one of its parameters from the container.
+//
+// The constructor may have any number of parameters, and must return the bean instance, optionally followed by an error.
+// Parameters of the type context.Context receive the context the bean is resolved with.
+//
+//	func(repo IRepo, log ILogger) IService
+//	func(repo IRepo, log ILogger) (IService, error)
+func (c *Container) newInjectedConstructor(t reflect.Type, ctor interface{}, opts *beanOptions) (func(ctx context.Context) (interface{}, error), []dependency, error) {
+	fn := reflect.ValueOf(ctor)
+	if ctor == nil || fn.Kind() != reflect.Func {
+		return nil, nil, fmt.Errorf("the constructor for type '%s' must be a function, got '%T'", t.Name(), ctor)
+	}
+
+	ft := fn.Type()
+	if ft.IsVariadic() {
+		return nil, nil, fmt.Errorf("the constructor for type '%s' cannot be variadic", t.Name())
+	}
+	if ft.NumOut() < 1 || ft.NumOut() > 2 || (ft.NumOut() == 2 && ft.Out(1) != errorType) {
+		return nil, nil, fmt.Errorf("the constructor for type '%s' must return the bean instance, optionally followed by an error", t.Name())
+	}
+	if out := ft.Out(0); !out.AssignableTo(t) && !(t.Kind() == reflect.Interface && out.Implements(t)) {
+		return nil, nil, fmt.Errorf("the constructor return type '%s' does not implement the provided type '%s'", out.String(), t.Name())
+	}
+
+	params := make([]dependency, ft.NumIn())
+	var deps []dependency
+	for i := range params {
+		params[i].t = ft.In(i)
+		if i < len(opts.paramNames) {
+			params[i].name = opts.paramNames[i]
+		}
+		// Handles resolve the bean when used, so they are not dependencies of the construction.
+		if params[i].t != contextType && !isHandle(params[i].t) {
+			deps = append(deps, params[i])
+		}
+	}
+
+	call := func(ctx context.Context) (interface{}, error) {
+		args := make([]reflect.Value, len(params))
+		for i, dep := range params {
+			if dep.t == contextType {
+				args[i] = reflect.ValueOf(&ctx).Elem()
+				continue
+			}
+			if isHandle(dep.t) {
+				args[i] = c.newHandle(ctx, dep.t, dep.name)
+				continue
+			}
+			val, err := c.getE(ctx, dep.t, dep.name)
+			if errors.Is(err, ErrCircularDependency) {
+				return nil, err
+			} else if err != nil {
+				return nil, fmt.Errorf("unable to resolve parameter %d (%s) of the constructor: %w", i, beanKey(dep.t, dep.name), err)
+			}
+			if args[i] = valueOf(val, dep.t); !args[i].Type().AssignableTo(dep.t) {
+				return nil, fmt.Errorf("unable to resolve parameter %d (%s) of the constructor: the resolved bean of type '%s' is not assignable", i, beanKey(dep.t, dep.name), args[i].Type().String())
+			}
+		}
+
+		out := fn.Call(args)
+		if len(out) == 2 && !out[1].IsNil() {
+			return nil, out[1].Interface().(error)
+		}
+		if isNilValue(out[0]) {
+			return nil, nil
+		}
+		return out[0].Interface(), nil
+	}
+
+	return call, deps, nil
+}
+
+// valueOf returns the reflect.Value of the provided instance to be used as an argument of the type 't'
+func valueOf(instance interface{}, t reflect.Type) reflect.Value {
+	if instance == nil {
+		return reflect.Zero(t)
+	}
+	return reflect.ValueOf(instance)
+}
+
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package beans_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+type composedService struct {
+	TestServiceImpl2
+	other IOther
+}
+
+func (s *composedService) GetName() string {
+	return "composed-" + s.other.Name()
+}
+
+func TestRegisterConstructor(t *testing.T) {
+	Convey("Testing RegisterConstructor", t, func() {
+		Convey("Parameters are resolved from the primary beans", t, func() {
+			before()
+			ShouldNotError(beans.Register((*IOther)(nil), "other", &OtherImpl1{name: "other"}))
+			ShouldNotError(beans.RegisterConstructor(ComponentType, "composed", func(other IOther) IService {
+				return &composedService{other: other}
+			}))
+			ShouldEqual("composed-other", Resolve("composed").GetName())
+		})
+		Convey("Parameters are resolved by name", t, func() {
+			before()
+			ShouldNotError(beans.Register((*IOther)(nil), "name1", &OtherImpl1{name: "name1"}))
+			ShouldNotError(beans.Register((*IOther)(nil), "name2", &OtherImpl1{name: "name2"}))
+			ShouldNotError(beans.RegisterConstructor(ComponentType, "composed", func(other IOther) (*composedService, error) {
+				return &composedService{other: other}, nil
+			}, beans.ParamNames("name2")))
+			ShouldEqual("composed-name2", Resolve("composed").GetName())
+		})
+		Convey("Singleton constructors are invoked once", t, func() {
+			before()
+			calls := 0
+			ShouldNotError(beans.Register((*IOther)(nil), "other", &OtherImpl1{name: "other"}))
+			ShouldNotError(beans.RegisterConstructor(ComponentType, "composed", func(other IOther) IService {
+				calls++
+				return &composedService{other: other}
+			}, beans.Singleton()))
+			ShouldEqual(Resolve("composed"), Resolve("composed"))
+			ShouldEqual(1, calls)
+		})
+		Convey("Constructor errors are surfaced", t, func() {
+			before()
+			expected := errors.New("unable to connect")
+			ShouldNotError(beans.RegisterConstructor(ComponentType, "failing", func() (IService, error) {
+				return nil, expected
+			}))
+			val, err := beans.ResolveE(ComponentType, "failing")
+			ShouldBeNil(val)
+			ShouldBeTrue(errors.Is(err, expected))
+		})
+		Convey("Unresolvable parameters are surfaced", t, func() {
+			before()
+			ShouldNotError(beans.RegisterConstructor(ComponentType, "composed", func(other IOther) IService {
+				return &composedService{other: other}
+			}))
+			_, err := beans.ResolveE(ComponentType, "composed")
+			ShouldBeTrue(errors.Is(err, beans.ErrTypeNotRegistered))
+		})
+		Convey("Invalid constructors are rejected", t, func() {
+			before()
+			ShouldError(beans.RegisterConstructor(ComponentType, "invalid", "not a function"))
+			ShouldError(beans.RegisterConstructor(ComponentType, "invalid", func() {}))
+			ShouldError(beans.RegisterConstructor(ComponentType, "invalid", func() (IService, string) { return nil, "" }))
+			ShouldError(beans.RegisterConstructor(ComponentType, "invalid", func() IOther { return nil }))
+			ShouldError(beans.RegisterConstructor(ComponentType, "invalid", func(...IOther) IService { return nil }))
+			ShouldBeFalse(beans.Exists(ComponentType, "invalid"))
+		})
+	})
+}
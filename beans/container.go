@@ -0,0 +1,579 @@
+package beans
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Container is an independent beans registry. The package level functions of this package operate over a default
+// container (see Default), however multiple containers may coexist in a single process, which is useful to run
+// independent applications or parallel tests without sharing registrations.
+//
+// A Container is safe for concurrent use by multiple goroutines.
+type Container struct {
+	// mux guards the registry and the configuration of the container, every field up to 'seq'. It is never held while
+	// a constructor, a condition or a decorator is invoked, so they are free to resolve or register other beans.
+	mux            sync.RWMutex
+	allowOverrides bool
+	dependencies   map[reflect.Type]*dependencyCollection
+	logger         ILogger
+	properties     map[string]string
+	sources        []*PropertySource
+	decorators     map[reflect.Type][]Decorator
+	processors     []*postProcessor
+
+	// pending holds the conditional registrations until the container is finalized, once 'finalized' is set the
+	// conditions are evaluated as soon as the beans are registered.
+	pending   []*pendingBean
+	finalized bool
+
+	// seq is the registration sequence, used to keep the registration order of the beans.
+	seq uint64
+
+	// buildMux guards 'discovered', which holds the dependencies of each bean discovered from nested resolutions,
+	// 'held', which holds the singleton constructions locked by each goroutine, and the state of the resolution
+	// chains, see enterConstruction.
+	buildMux   sync.Mutex
+	discovered map[dependency][]dependency
+	held       map[uint64][]*resolutionFrame
+
+	// eventMux guards 'subscribers', the subscribers of the container events.
+	eventMux    sync.RWMutex
+	subscribers []*subscriber
+
+	// lifeMux guards 'created', which holds the singletons in the order they were created. startMux serializes Start,
+	// so concurrent invocations do not start the same singleton twice.
+	lifeMux  sync.Mutex
+	created  []*createdBean
+	startMux sync.Mutex
+
+	// profileMux guards 'profiles', the active profiles, and 'active', the set of the active profiles.
+	profileMux sync.RWMutex
+	profiles   []string
+	active     profileSet
+}
+
+// NewContainer creates a new empty beans container. The active profiles of the container are read from the
+// BEANS_PROFILES_ACTIVE environment variable, see SetActiveProfiles.
+func NewContainer() *Container {
+	c := &Container{
+		dependencies: map[reflect.Type]*dependencyCollection{},
+		discovered:   map[dependency][]dependency{},
+		held:         map[uint64][]*resolutionFrame{},
+	}
+	c.setActiveProfiles(profilesFromEnv())
+	return c
+}
+
+// SetLogger sets an implementation of ILogger to be used as the logger for this container. If no logger is set,
+// the package logger is used (see beans.SetLogger).
+func (c *Container) SetLogger(l ILogger) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.logger = l
+}
+
+// Clear clears all registered dependencies. It requires Allow Overrides to be set to TRUE. Use this with caution, it was meant for testing purposes only.
+func (c *Container) Clear() error {
+	if err := c.clear(); err != nil {
+		return err
+	}
+	c.publish(ContainersCleared{EventInfo: newEventInfo(nil, "")})
+	return nil
+}
+
+func (c *Container) clear() error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if !c.allowOverrides {
+		return errors.New("unable to clear beans while Allow Overrides is set to FALSE")
+	}
+	c.dependencies = map[reflect.Type]*dependencyCollection{}
+	c.pending = nil
+	c.finalized = false
+	c.decorators = nil
+	c.processors = nil
+
+	c.lifeMux.Lock()
+	c.created = nil
+	c.lifeMux.Unlock()
+	return nil
+}
+
+// SetAllowOverrides is normally used when testing. It allows a registered bean to be overwritten by another implementation, like a Mock
+func (c *Container) SetAllowOverrides(allow bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.allowOverrides = allow
+}
+
+// AllowOverrides indicates whether a registered bean may be overwritten by another implementation. See SetAllowOverrides
+func (c *Container) AllowOverrides() bool {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return c.allowOverrides
+}
+
+// Resolve resolves the bean type by the given name. See beans.Resolve
+func (c *Container) Resolve(ref interface{}, name string) interface{} {
+	return c.Get(getType(ref), name)
+}
+
+// ResolveE resolves the bean type by the given name, returning an error if the bean cannot be resolved. See beans.ResolveE
+func (c *Container) ResolveE(ref interface{}, name string) (interface{}, error) {
+	return c.GetE(getType(ref), name)
+}
+
+// ResolveCtx resolves the bean type by the given name, using the scope of the provided context to resolve context
+// scoped beans. See beans.ResolveCtx
+func (c *Container) ResolveCtx(ctx context.Context, ref interface{}, name string) interface{} {
+	return c.logIfError(c.ResolveCtxE(ctx, ref, name))
+}
+
+// ResolveCtxE resolves the bean type by the given name, using the scope of the provided context to resolve context
+// scoped beans, and returns an error if the bean cannot be resolved. See beans.ResolveCtx
+func (c *Container) ResolveCtxE(ctx context.Context, ref interface{}, name string) (interface{}, error) {
+	return c.getE(ctx, getType(ref), name)
+}
+
+// Primary resolves the bean type using the registered bean set as primary. See beans.Primary
+func (c *Container) Primary(ref interface{}) interface{} {
+	return c.GetPrimary(getType(ref))
+}
+
+// PrimaryE resolves the bean type using the registered bean set as primary, returning an error if the bean cannot be
+// resolved. See beans.PrimaryE
+func (c *Container) PrimaryE(ref interface{}) (interface{}, error) {
+	return c.GetPrimaryE(getType(ref))
+}
+
+// Get gets the the instance by the specified name. Failures are reported to the logger and nil is returned, use GetE
+// to obtain the error instead.
+func (c *Container) Get(t reflect.Type, name string) interface{} {
+	return c.logIfError(c.GetE(t, name))
+}
+
+// GetE gets the the instance by the specified name. If the name is empty, the primary bean is returned. If the bean
+// cannot be resolved, a *BeanError wrapping one of ErrTypeNotRegistered, ErrBeanNotFound, ErrNoPrimary,
+// ErrAmbiguousPrimary or ErrNilInstance is returned.
+func (c *Container) GetE(t reflect.Type, name string) (interface{}, error) {
+	return c.getE(context.Background(), t, name)
+}
+
+// GetCtxE gets the the instance by the specified name, using the scope of the provided context to resolve context
+// scoped beans. See beans.ResolveCtx
+func (c *Container) GetCtxE(ctx context.Context, t reflect.Type, name string) (interface{}, error) {
+	return c.getE(ctx, t, name)
+}
+
+func (c *Container) getE(ctx context.Context, t reflect.Type, name string) (interface{}, error) {
+	expanded, err := c.expandName(t, name)
+	if err != nil {
+		return nil, c.resolveFailed(t, name, err)
+	}
+	if expanded == "" {
+		return c.getPrimaryE(ctx, t)
+	}
+
+	instance, ctor, err := c.instanceOf(ctx, t, expanded)
+	if err != nil {
+		return nil, c.resolveFailed(t, expanded, err)
+	}
+	ctor.resolutions.Add(1)
+	return triggerOnResolve(instance), nil
+}
+
+// instanceOf returns the instance of the bean by the given name, constructing it if required, along with the
+// registration of the bean.
+func (c *Container) instanceOf(ctx context.Context, t reflect.Type, name string) (*instanceInfo, *constructorInfo, error) {
+	active := c.activeProfiles()
+	c.mux.RLock()
+	dep, ok := c.dependencies[t]
+	var ctorInfo *constructorInfo
+	if ok {
+		ctorInfo = dep.ctors[name]
+	}
+	c.mux.RUnlock()
+
+	if !ok {
+		return nil, nil, errTypeNotRegistered(t)
+	}
+	if ctorInfo == nil {
+		return nil, nil, newBeanError(t, name, ErrBeanNotFound, "dependency %s not registered, unable to resolve", name)
+	}
+	if !ctorInfo.isActive(active) {
+		return nil, nil, errProfileInactive(dependency{t: t, name: name}, ctorInfo.profiles, active)
+	}
+
+	if ctorInfo.needsConstruction() {
+		var leave func()
+		var err error
+		if ctx, leave, err = c.enterConstruction(ctx, dependency{t: t, name: name}); err != nil {
+			return nil, nil, err
+		}
+		defer leave()
+	} else {
+		c.discoverFrom(ctx, dependency{t: t, name: name})
+	}
+
+	var instance *instanceInfo
+	var created bool
+	var err error
+	switch ctorInfo.scope {
+	case ScopeContext:
+		instance, created, err = c.scopedInstanceOf(ctx, dependency{t: t, name: name}, ctorInfo)
+	case ScopeSingleton:
+		instance, created, err = c.singletonOf(ctx, ctorInfo)
+	default:
+		instance, err = ctorInfo.construct(ctx)
+		created = err == nil
+	}
+	if err != nil {
+		if errors.Is(err, ErrCircularDependency) {
+			return nil, nil, err
+		}
+		return nil, nil, &BeanError{Type: t, Name: name, Err: err}
+	}
+	if created && ctorInfo.scope == ScopeSingleton {
+		c.recordCreated(dependency{t: t, name: name}, instance.instance)
+		c.publish(SingletonCreated{EventInfo: newEventInfo(t, name), Instance: instance.instance})
+	}
+	return instance, ctorInfo, nil
+}
+
+// singletonOf returns the singleton instance of the bean, and whether the instance was created by this call. The
+// constructor is invoked only once, any other goroutine resolving the same bean while it is being constructed waits for
+// the instance to be ready, unless it holds a bean the construction depends on. If the constructor fails, the instance
+// is not stored so the construction can be attempted again.
+func (c *Container) singletonOf(ctx context.Context, ctor *constructorInfo) (*instanceInfo, bool, error) {
+	if instance := ctor.instance.Load(); instance != nil {
+		return instance, false, nil
+	}
+
+	release, err := c.acquire(ctx, &ctor.lock, true)
+	if err != nil {
+		return nil, false, err
+	}
+	defer release()
+
+	if instance := ctor.instance.Load(); instance != nil {
+		return instance, false, nil
+	}
+
+	// Singletons are not bound to the scope of the resolution, only the resolution chain is kept.
+	instance, err := ctor.construct(withFrame(context.Background(), frameFrom(ctx)))
+	if err != nil {
+		return nil, false, err
+	}
+	ctor.instance.Store(instance)
+	return instance, true, nil
+}
+
+// GetPrimary gets the primary dependency registered in this container, same as primary but with a reflect.Type.
+// Failures are reported to the logger and nil is returned, use GetPrimaryE to obtain the error instead.
+func (c *Container) GetPrimary(t reflect.Type) interface{} {
+	return c.logIfError(c.GetPrimaryE(t))
+}
+
+// GetPrimaryE gets the primary dependency registered in this container, returning an error if it cannot be resolved.
+//
+// If no bean has been set as primary and a single bean is registered for the type, that bean is considered primary.
+// Only the beans active for the active profiles are considered.
+func (c *Container) GetPrimaryE(t reflect.Type) (interface{}, error) {
+	return c.getPrimaryE(context.Background(), t)
+}
+
+func (c *Container) getPrimaryE(ctx context.Context, t reflect.Type) (interface{}, error) {
+	active := c.activeProfiles()
+	c.mux.RLock()
+	dep, ok := c.dependencies[t]
+	name, count, excluded := "", 0, 0
+	if ok {
+		name = c.primaryNameOf(dep, active)
+		for _, ctor := range dep.ctors {
+			if ctor.isActive(active) {
+				count++
+			} else {
+				excluded++
+			}
+		}
+	}
+	c.mux.RUnlock()
+
+	switch {
+	case !ok:
+		return nil, c.resolveFailed(t, "", errTypeNotRegistered(t))
+	case name != "":
+		return c.getE(ctx, t, name)
+	case count > 1:
+		return nil, c.resolveFailed(t, "", newBeanError(t, "", ErrAmbiguousPrimary, "no primary dependency found for type '%s', %d dependencies are registered and none is set as primary", t.Name(), count))
+	case excluded > 0:
+		return nil, c.resolveFailed(t, "", newBeanError(t, "", ErrNoPrimary, "no primary dependency found for type '%s', %d dependencies are not active for the active profiles %s", t.Name(), excluded, active.String()))
+	}
+	return nil, c.resolveFailed(t, "", newBeanError(t, "", ErrNoPrimary, "no primary dependency found for type '%s'", t.Name()))
+}
+
+// RegisterFuncByType registers a bean function retriever into the container. See beans.RegisterFuncByType
+func (c *Container) RegisterFuncByType(t reflect.Type, name string, fn func() interface{}, singleton ...bool) error {
+	info := &constructorInfo{
+		ctor: func(context.Context) (interface{}, error) { return fn(), nil },
+	}
+	if len(singleton) > 0 && singleton[0] {
+		info.scope = ScopeSingleton
+	}
+	return c.register(t, name, info)
+}
+
+// RegisterConstructorByType registers a constructor function into the container, which parameters are resolved from
+// the container when the bean is constructed. See beans.RegisterConstructor
+func (c *Container) RegisterConstructorByType(t reflect.Type, name string, ctor interface{}, opts ...Option) error {
+	options := newBeanOptions(opts...)
+	fn, deps, err := c.newInjectedConstructor(t, ctor, options)
+	if err != nil {
+		return err
+	}
+	if options.autoInject {
+		fn = c.withInjection(fn)
+		deps = append(deps, injectionDependencies(reflect.TypeOf(ctor).Out(0))...)
+	}
+
+	info := &constructorInfo{
+		ctor:  fn,
+		scope: options.scope,
+		deps:  deps,
+	}
+	info.setConcreteType(reflect.TypeOf(ctor).Out(0))
+	options.apply(info)
+	return c.register(t, name, info)
+}
+
+// RegisterConstructor registers a constructor function into the container, which parameters are resolved from the
+// container when the bean is constructed. See beans.RegisterConstructor
+func (c *Container) RegisterConstructor(interfaceRef interface{}, name string, ctor interface{}, opts ...Option) error {
+	return c.RegisterConstructorByType(getType(interfaceRef), name, ctor, opts...)
+}
+
+func (c *Container) register(t reflect.Type, name string, info *constructorInfo) error {
+	if name == "" {
+		return errors.New("the name cannot be empty")
+	}
+	if err := validateProfiles(info.profiles); err != nil {
+		return err
+	}
+	if len(info.conditions) == 0 {
+		return c.add(t, name, info)
+	}
+
+	c.mux.Lock()
+	if !c.finalized {
+		c.pending = append(c.pending, &pendingBean{dependency: dependency{t: t, name: name}, info: info})
+		c.mux.Unlock()
+		return nil
+	}
+	c.mux.Unlock()
+	return c.registerIfMatches(t, name, info)
+}
+
+// add adds the bean to the registered dependencies.
+func (c *Container) add(t reflect.Type, name string, info *constructorInfo) error {
+	overridden, err := c.store(t, name, info)
+	if err != nil {
+		return err
+	}
+	if overridden {
+		c.publish(BeanOverridden{EventInfo: newEventInfo(t, name)})
+	} else {
+		c.publish(BeanRegistered{EventInfo: newEventInfo(t, name)})
+	}
+	return nil
+}
+
+// store stores the bean into the registered dependencies, returning whether a bean by the same name was replaced.
+func (c *Container) store(t reflect.Type, name string, info *constructorInfo) (bool, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if !containsType(c.dependencies, t) {
+		c.dependencies[t] = &dependencyCollection{
+			ctors: map[string]*constructorInfo{},
+		}
+	}
+
+	previous, overridden := c.dependencies[t].ctors[name]
+	if overridden && !c.allowOverrides {
+		return false, fmt.Errorf("a dependency with name %s is already registered", name)
+	}
+	if overridden {
+		info.overrides = append(append([]BeanOverride{}, previous.overrides...), BeanOverride{
+			Time:         time.Now(),
+			PreviousType: previous.concreteType(),
+		})
+	}
+
+	c.seq++
+	info.seq = c.seq
+	info.beforeInit = func(instance interface{}) (interface{}, error) {
+		return c.beforeInit(t, name, instance)
+	}
+	info.afterInit = func(instance interface{}) (interface{}, error) {
+		return c.afterInit(t, name, instance)
+	}
+	c.dependencies[t].ctors[name] = info
+
+	c.buildMux.Lock()
+	delete(c.discovered, dependency{t: t, name: name})
+	c.buildMux.Unlock()
+	return overridden, nil
+}
+
+// RegisterFunc registers a bean function retriever into the container. See beans.RegisterFunc
+func (c *Container) RegisterFunc(interfaceRef interface{}, name string, fn func() interface{}, singleton ...bool) error {
+	return c.RegisterFuncByType(getType(interfaceRef), name, fn, singleton...)
+}
+
+// RegisterByType registers a bean singleton instance into the container.
+func (c *Container) RegisterByType(t reflect.Type, name string, component interface{}, opts ...Option) error {
+	if component == nil {
+		return errors.New("the component cannot be nil")
+	}
+	ct := reflect.TypeOf(component)
+	if !ct.AssignableTo(t) {
+		return fmt.Errorf("the component type '%s' does not implement the provided type '%s'", ct.Name(), t.Name())
+	}
+
+	info := &constructorInfo{
+		ctor:  func(context.Context) (interface{}, error) { return component, nil },
+		scope: ScopeSingleton,
+	}
+	info.setConcreteType(ct)
+	options := newBeanOptions(opts...)
+	options.apply(info)
+	if options.autoInject {
+		info.ctor = c.withInjection(info.ctor)
+		info.deps = injectionDependencies(ct)
+	}
+	return c.register(t, name, info)
+}
+
+// Register registers a bean singleton instance into the container. See beans.Register
+func (c *Container) Register(interfaceRef interface{}, name string, component interface{}, opts ...Option) error {
+	return c.RegisterByType(getType(interfaceRef), name, component, opts...)
+}
+
+// SetPrimaryByType sets the primary bean name to be used.
+func (c *Container) SetPrimaryByType(t reflect.Type, name string, replace ...bool) error {
+	name, err := c.expandName(t, name)
+	if err != nil {
+		return err
+	}
+
+	previous, changed, err := c.setPrimary(t, name, len(replace) > 0 && replace[0])
+	if changed {
+		c.publish(PrimaryChanged{EventInfo: newEventInfo(t, name), Previous: previous})
+	}
+	return err
+}
+
+func (c *Container) setPrimary(t reflect.Type, name string, replace bool) (string, bool, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if !containsType(c.dependencies, t) {
+		return "", false, errTypeNotRegistered(t)
+	}
+
+	dep := c.dependencies[t]
+	if _, ok := dep.ctors[name]; ok {
+		previous := dep.primary
+		if dep.primary == "" || (dep.primary != "" && replace) {
+			dep.primary = name
+		}
+		return previous, previous != dep.primary, nil
+	}
+
+	return "", false, newBeanError(t, name, ErrBeanNotFound, "dependency %s not registered, unable to set as primary", name)
+}
+
+// SetPrimary sets the primary bean name to be used. See beans.SetPrimary
+func (c *Container) SetPrimary(interfaceRef interface{}, name string, replace ...bool) error {
+	return c.SetPrimaryByType(getType(interfaceRef), name, replace...)
+}
+
+// GetPrimaryNameByType returns the name of the primary bean. Returns an empty string if no beans exist as primary.
+func (c *Container) GetPrimaryNameByType(t reflect.Type) string {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	if v, ok := c.dependencies[t]; ok {
+		return v.primary
+	}
+	return ""
+}
+
+// GetPrimaryName returns the name of the primary bean. Returns an empty string if no beans exist as primary.
+func (c *Container) GetPrimaryName(interfaceRef interface{}) string {
+	return c.GetPrimaryNameByType(getType(interfaceRef))
+}
+
+// ExistsByType indicates if a dependency by the given name exists and it is active for the active profiles
+func (c *Container) ExistsByType(t reflect.Type, name string) bool {
+	name = c.expandNameOrKeep(t, name)
+	active := c.activeProfiles()
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	if !containsType(c.dependencies, t) {
+		return false
+	}
+
+	ctor, ok := c.dependencies[t].ctors[name]
+	return ok && ctor.isActive(active)
+}
+
+// Exists indicates if a dependency by the given name exists. See beans.Exists
+func (c *Container) Exists(interfaceRef interface{}, name string) bool {
+	return c.ExistsByType(getType(interfaceRef), name)
+}
+
+// primaryNameOf returns the name of the primary bean of the collection among the beans active for the provided
+// profiles. If the bean set as primary is not active and a single active bean is registered, that bean is considered
+// primary. Must be invoked while holding 'mux'
+func (c *Container) primaryNameOf(dep *dependencyCollection, active profileSet) string {
+	if ctor, ok := dep.ctors[dep.primary]; ok && ctor.isActive(active) {
+		return dep.primary
+	}
+	name := ""
+	for n, ctor := range dep.ctors {
+		if !ctor.isActive(active) {
+			continue
+		}
+		if name != "" {
+			return ""
+		}
+		name = n
+	}
+	return name
+}
+
+func (c *Container) logIfError(val interface{}, err error) interface{} {
+	if err != nil {
+		c.log().Error(err)
+	}
+	return val
+}
+
+func (c *Container) log() ILogger {
+	c.mux.RLock()
+	l := c.logger
+	c.mux.RUnlock()
+
+	if l != nil {
+		return l
+	}
+	return log()
+}
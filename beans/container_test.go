@@ -0,0 +1,61 @@
+package beans_test
+
+import (
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+func TestContainerIsolation(t *testing.T) {
+	Convey("Testing independent containers", t, func() {
+		before()
+		c1 := beans.NewContainer()
+		c2 := beans.NewContainer()
+
+		Convey("Registrations do not leak between containers", t, func() {
+			ShouldNotError(c1.Register((*IOther)(nil), "name", &OtherImpl1{name: "c1"}))
+			ShouldNotError(c2.Register((*IOther)(nil), "name", &OtherImpl1{name: "c2"}))
+
+			ShouldEqual("c1", c1.Resolve((*IOther)(nil), "name").(IOther).Name())
+			ShouldEqual("c2", c2.Resolve((*IOther)(nil), "name").(IOther).Name())
+			ShouldBeFalse(beans.Exists((*IOther)(nil), "name"))
+		})
+		Convey("Primary, overrides and clear are per container", t, func() {
+			ShouldNotError(c1.RegisterFunc((*IOther)(nil), "other", func() interface{} {
+				return &OtherImpl1{name: "other"}
+			}, true))
+			ShouldNotError(c1.SetPrimary((*IOther)(nil), "other"))
+			ShouldEqual("other", c1.GetPrimaryName((*IOther)(nil)))
+			ShouldEqual("other", c1.Primary((*IOther)(nil)).(IOther).Name())
+			ShouldEqual("", c2.GetPrimaryName((*IOther)(nil)))
+
+			ShouldError(c1.Clear())
+			c1.SetAllowOverrides(true)
+			ShouldNotError(c1.Clear())
+			ShouldBeFalse(c1.Exists((*IOther)(nil), "name"))
+			ShouldBeTrue(c2.Exists((*IOther)(nil), "name"))
+			ShouldError(c2.Register((*IOther)(nil), "name", &OtherImpl1{name: "c2"}))
+		})
+		Convey("InitComponents only constructs the container singletons", t, func() {
+			built := 0
+			ShouldNotError(c1.RegisterFunc((*IOther)(nil), "lazy", func() interface{} {
+				built++
+				return &OtherImpl1{name: "lazy"}
+			}, true))
+			c2.InitComponents()
+			ShouldEqual(0, built)
+			c1.InitComponents()
+			ShouldEqual(1, built)
+		})
+	})
+}
+
+func TestDefaultContainer(t *testing.T) {
+	Convey("Testing the package functions delegate to the default container", t, func() {
+		before()
+		ShouldNotError(beans.Default().Register((*IOther)(nil), "name", &OtherImpl1{name: "default"}))
+		ShouldBeTrue(beans.Exists((*IOther)(nil), "name"))
+		ShouldEqual("default", beans.Resolve((*IOther)(nil), "name").(IOther).Name())
+	})
+}
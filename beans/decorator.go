@@ -0,0 +1,46 @@
+package beans
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Decorator wraps a bean instance, returning the instance to be used instead. See beans.Decorate
+type Decorator func(inner interface{}) interface{}
+
+// DecorateByType registers a decorator for all the beans of the provided type. See beans.Decorate
+func (c *Container) DecorateByType(t reflect.Type, decorator Decorator) error {
+	if decorator == nil {
+		return errors.New("the decorator cannot be nil")
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.decorators == nil {
+		c.decorators = map[reflect.Type][]Decorator{}
+	}
+	c.decorators[t] = append(c.decorators[t], decorator)
+	return nil
+}
+
+// Decorate registers a decorator for all the beans of the referenced type. See beans.Decorate
+func (c *Container) Decorate(interfaceRef interface{}, decorator Decorator) error {
+	return c.DecorateByType(getType(interfaceRef), decorator)
+}
+
+// decorate applies the decorators registered for the type to a constructed instance, in the order they were
+// registered.
+func (c *Container) decorate(t reflect.Type, instance interface{}) (interface{}, error) {
+	c.mux.RLock()
+	decorators := c.decorators[t]
+	c.mux.RUnlock()
+
+	for i, decorator := range decorators {
+		decorated := decorator(instance)
+		if decorated == nil || !reflect.TypeOf(decorated).AssignableTo(t) {
+			return nil, fmt.Errorf("the decorator %d of type '%s' returned '%T', which does not implement the type", i+1, t.Name(), decorated)
+		}
+		instance = decorated
+	}
+	return instance, nil
+}
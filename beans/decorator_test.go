@@ -0,0 +1,70 @@
+package beans_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+type prefixedOther struct {
+	inner  IOther
+	prefix string
+}
+
+func (p *prefixedOther) Name() string {
+	return p.prefix + p.inner.Name()
+}
+
+func prefixWith(prefix string) beans.Decorator {
+	return func(inner interface{}) interface{} {
+		return &prefixedOther{inner: inner.(IOther), prefix: prefix}
+	}
+}
+
+func TestDecorators(t *testing.T) {
+	Convey("Testing decorators", t, func() {
+		ref := (*IOther)(nil)
+
+		Convey("Decorators are chained in the order they were registered", t, func() {
+			c := beans.NewContainer()
+			ShouldNotError(c.Register(ref, "repo", &OtherImpl1{name: "repo"}))
+			ShouldNotError(c.Decorate(ref, prefixWith("cached-")))
+			ShouldNotError(c.Decorate(ref, prefixWith("metered-")))
+
+			ShouldEqual("metered-cached-repo", c.Resolve(ref, "repo").(IOther).Name())
+			ShouldEqual(c.Resolve(ref, "repo"), c.Resolve(ref, "repo"))
+		})
+		Convey("Singletons are decorated once and prototypes on each construction", t, func() {
+			c := beans.NewContainer()
+			decorated := 0
+			ShouldNotError(c.Decorate(ref, func(inner interface{}) interface{} {
+				decorated++
+				return &prefixedOther{inner: inner.(IOther)}
+			}))
+			ShouldNotError(c.RegisterFunc(ref, "singleton", func() interface{} { return &OtherImpl1{} }, true))
+			ShouldNotError(c.RegisterFunc(ref, "prototype", func() interface{} { return &OtherImpl1{} }))
+
+			for i := 0; i < 3; i++ {
+				c.Resolve(ref, "singleton")
+			}
+			ShouldEqual(1, decorated)
+			for i := 0; i < 3; i++ {
+				c.Resolve(ref, "prototype")
+			}
+			ShouldEqual(4, decorated)
+		})
+		Convey("Decorators that do not return the bean type fail the construction", t, func() {
+			c := beans.NewContainer()
+			ShouldNotError(c.Register(ref, "repo", &OtherImpl1{name: "repo"}))
+			ShouldNotError(c.Decorate(ref, func(interface{}) interface{} { return "not an IOther" }))
+
+			_, err := c.ResolveE(ref, "repo")
+			var beanErr *beans.BeanError
+			ShouldBeTrue(errors.As(err, &beanErr))
+			ShouldEqual("repo", beanErr.Name)
+			ShouldError(c.Decorate(ref, nil))
+		})
+	})
+}
@@ -0,0 +1,95 @@
+package beans
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+// BeanDescriptor describes a registered bean. See beans.ListBeans
+type BeanDescriptor struct {
+	// Type is the type the bean is registered for, normally an interface.
+	Type reflect.Type
+	// Name is the name the bean is registered by.
+	Name string
+	// Primary indicates whether the bean is the primary bean of its type, either because it was set as primary or
+	// because it is the only active bean of its type.
+	Primary bool
+	// Scope is the lifetime of the bean instances.
+	Scope Scope
+	// Instantiated indicates whether an instance of the bean has been constructed. For singletons it indicates whether
+	// the singleton instance exists.
+	Instantiated bool
+	// ConcreteType is the type of the bean instances, nil until it is known. It is known from the registration for beans
+	// registered as instances and for constructors that return a concrete type, otherwise it is known once the first
+	// instance is constructed.
+	ConcreteType reflect.Type
+	// Overridden indicates whether the bean replaced a previous registration by the same name.
+	Overridden bool
+	// Overrides holds the history of the registrations by the same name replaced by this bean, oldest first.
+	Overrides []BeanOverride
+	// Profiles are the profiles the bean is registered for, see beans.Profiles
+	Profiles []string
+	// Active indicates whether the bean matches the active profiles.
+	Active bool
+	// Constructions is the number of instances constructed, at most 1 for singletons.
+	Constructions int64
+	// LastConstructionTime and TotalConstructionTime are the time taken by the last construction and by all the
+	// constructions of the bean, including the time taken to construct the dependencies resolved by the constructor.
+	LastConstructionTime  time.Duration
+	TotalConstructionTime time.Duration
+	// Resolutions is the number of times the bean was resolved, either by its name, as the primary bean of its type or
+	// along with all the beans of its type, including the resolutions as a dependency of other beans.
+	Resolutions int64
+
+	seq uint64
+}
+
+// BeanOverride is a registration replaced by another one by the same name.
+type BeanOverride struct {
+	// Time is the moment the registration was replaced.
+	Time time.Time
+	// PreviousType is the concrete type of the replaced registration, nil if it was not known.
+	PreviousType reflect.Type
+}
+
+// ListBeans returns a descriptor for every registered bean, in the order they were registered. See beans.ListBeans
+func (c *Container) ListBeans() []BeanDescriptor {
+	active := c.activeProfiles()
+	c.mux.RLock()
+	var ret []BeanDescriptor
+	for t, dep := range c.dependencies {
+		primary := c.primaryNameOf(dep, active)
+		for name, ctor := range dep.ctors {
+			d := BeanDescriptor{
+				Type:         t,
+				Name:         name,
+				Primary:      name == primary,
+				Scope:        ctor.scope,
+				Instantiated: ctor.constructions.Load() > 0,
+				ConcreteType: ctor.concreteType(),
+				Overridden:   len(ctor.overrides) > 0,
+				Overrides:    append([]BeanOverride{}, ctor.overrides...),
+				Profiles:     append([]string{}, ctor.profiles...),
+				Active:       ctor.isActive(active),
+
+				Constructions:         ctor.constructions.Load(),
+				LastConstructionTime:  time.Duration(ctor.lastConstruction.Load()),
+				TotalConstructionTime: time.Duration(ctor.totalConstruction.Load()),
+				Resolutions:           ctor.resolutions.Load(),
+
+				seq: ctor.seq,
+			}
+			if ctor.scope == ScopeSingleton {
+				d.Instantiated = ctor.isInstantiated()
+			}
+			ret = append(ret, d)
+		}
+	}
+	c.mux.RUnlock()
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].seq < ret[j].seq
+	})
+	return ret
+}
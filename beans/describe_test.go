@@ -0,0 +1,66 @@
+package beans_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+func TestListBeans(t *testing.T) {
+	Convey("Testing the registered beans descriptors", t, func() {
+		ref := (*IOther)(nil)
+		otherType := reflect.TypeOf(ref).Elem()
+		c := beans.NewContainer()
+		built := 0
+		ShouldNotError(c.Register(ref, "instance", &OtherImpl1{name: "instance"}))
+		ShouldNotError(c.RegisterFunc(ref, "lazy", func() interface{} {
+			built++
+			return &OtherImpl1{name: "lazy"}
+		}, true))
+		ShouldNotError(c.RegisterConstructor(ComponentType, "svc", func() *TestServiceImpl2 {
+			built++
+			return &TestServiceImpl2{}
+		}, beans.Profiles("prod")))
+		ShouldNotError(c.SetPrimary(ref, "lazy"))
+
+		Convey("Listing the beans does not construct them", t, func() {
+			list := c.ListBeans()
+			ShouldEqual(0, built)
+			ShouldEqual(3, len(list))
+
+			ShouldEqual(otherType, list[0].Type)
+			ShouldEqual("instance", list[0].Name)
+			ShouldBeFalse(list[0].Primary)
+			ShouldEqual(beans.ScopeSingleton, list[0].Scope)
+			ShouldBeFalse(list[0].Instantiated)
+			ShouldEqual(reflect.TypeOf(&OtherImpl1{}), list[0].ConcreteType)
+
+			ShouldBeTrue(list[1].Primary)
+			ShouldBeNil(list[1].ConcreteType)
+
+			ShouldEqual(beans.ScopePrototype, list[2].Scope)
+			ShouldEqual(reflect.TypeOf(&TestServiceImpl2{}), list[2].ConcreteType)
+			ShouldEqual([]string{"prod"}, list[2].Profiles)
+			ShouldBeFalse(list[2].Active)
+		})
+		Convey("Instantiation, concrete types and overrides are reported once known", t, func() {
+			c.Resolve(ref, "lazy")
+			c.SetAllowOverrides(true)
+			ShouldNotError(c.Register(ref, "instance", &OtherImpl1{name: "mock"}))
+
+			list := c.ListBeans()
+			ShouldEqual(1, built)
+			ShouldEqual("lazy", list[0].Name)
+			ShouldBeTrue(list[0].Instantiated)
+			ShouldEqual(reflect.TypeOf(&OtherImpl1{}), list[0].ConcreteType)
+			ShouldEqual(int64(1), list[0].Constructions)
+			ShouldEqual(list[0].LastConstructionTime, list[0].TotalConstructionTime)
+			ShouldEqual("instance", list[2].Name)
+			ShouldBeTrue(list[2].Overridden)
+			ShouldEqual(1, len(list[2].Overrides))
+			ShouldEqual(reflect.TypeOf(&OtherImpl1{}), list[2].Overrides[0].PreviousType)
+		})
+	})
+}
@@ -0,0 +1,116 @@
+package beans
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrTypeNotRegistered indicates that no beans have been registered for the requested type.
+	ErrTypeNotRegistered = errors.New("type not registered")
+
+	// ErrBeanNotFound indicates that no bean was registered by the requested name for the requested type.
+	ErrBeanNotFound = errors.New("bean not found")
+
+	// ErrNoPrimary indicates that no bean is available to be used as the primary bean of the requested type.
+	ErrNoPrimary = errors.New("no primary bean")
+
+	// ErrAmbiguousPrimary indicates that multiple beans are registered for the requested type and none of them has
+	// been set as primary.
+	ErrAmbiguousPrimary = errors.New("ambiguous primary bean")
+
+	// ErrNilInstance indicates that the constructor of a bean returned nil.
+	ErrNilInstance = errors.New("constructor returned nil")
+)
+
+// BeanError is the error returned when an operation over a bean fails. It carries the bean type and name involved,
+// and wraps one of the sentinel errors of this package, so it can be inspected with errors.Is and errors.As
+//
+//	if errors.Is(err, beans.ErrBeanNotFound) { ...
+//
+//	var beanErr *beans.BeanError
+//	if errors.As(err, &beanErr) {
+//	    fmt.Println(beanErr.Type, beanErr.Name)
+//	}
+type BeanError struct {
+	// Type is the bean type involved in the failure.
+	Type reflect.Type
+	// Name is the bean name involved in the failure, empty when the failure is not related to a specific bean name.
+	Name string
+	// Err is the cause of the failure.
+	Err error
+
+	msg string
+}
+
+func (e *BeanError) Error() string {
+	if e.msg != "" {
+		return e.msg
+	}
+	return fmt.Sprintf("%s: %v", beanKey(e.Type, e.Name), e.Err)
+}
+
+// Unwrap returns the cause of the failure.
+func (e *BeanError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError aggregates multiple errors produced by a single operation, such as injecting the fields of a struct.
+// Each one of the aggregated errors can be inspected with errors.Is and errors.As
+type MultiError struct {
+	// Errors contains all the aggregated errors.
+	Errors []error
+
+	msg string
+}
+
+func (e *MultiError) Error() string {
+	lines := make([]string, 0, len(e.Errors)+1)
+	lines = append(lines, e.msg)
+	for _, err := range e.Errors {
+		lines = append(lines, "  - "+strings.ReplaceAll(err.Error(), "\n", "\n    "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap returns the aggregated errors.
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
+// newMultiError returns a *MultiError with the provided errors, or nil if no errors are provided.
+func newMultiError(msg string, errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return &MultiError{Errors: errs, msg: msg}
+}
+
+func newBeanError(t reflect.Type, name string, err error, format string, args ...interface{}) *BeanError {
+	return &BeanError{
+		Type: t,
+		Name: name,
+		Err:  err,
+		msg:  fmt.Sprintf(format, args...),
+	}
+}
+
+func errTypeNotRegistered(t reflect.Type) error {
+	return newBeanError(t, "", ErrTypeNotRegistered, "no dependencies found for type %s, unable to resolve", t.Name())
+}
+
+// beanKey returns a readable representation of a bean as 'Type/name'.
+func beanKey(t reflect.Type, name string) string {
+	typeName := "<nil>"
+	if t != nil {
+		if typeName = t.Name(); typeName == "" {
+			typeName = t.String()
+		}
+	}
+	if name == "" {
+		return typeName
+	}
+	return typeName + "/" + name
+}
@@ -0,0 +1,88 @@
+package beans_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+func TestResolveE(t *testing.T) {
+	Convey("Testing ResolveE and PrimaryE errors", t, func() {
+		Convey("Successful", t, func() {
+			before()
+			val, err := beans.ResolveE(ComponentType, "default")
+			ShouldNotError(err)
+			ShouldEqual("bean1", val.(IService).GetName())
+			val, err = beans.PrimaryE(ComponentType)
+			ShouldNotError(err)
+			ShouldEqual("bean1", val.(IService).GetName())
+		})
+		Convey("Type not registered", t, func() {
+			before()
+			_, err := beans.ResolveE((*IOther)(nil), "something")
+			ShouldBeTrue(errors.Is(err, beans.ErrTypeNotRegistered))
+			_, err = beans.PrimaryE((*IOther)(nil))
+			ShouldBeTrue(errors.Is(err, beans.ErrTypeNotRegistered))
+
+			var beanErr *beans.BeanError
+			ShouldBeTrue(errors.As(err, &beanErr))
+			ShouldEqual(reflect.TypeOf((*IOther)(nil)).Elem(), beanErr.Type)
+		})
+		Convey("Bean not found", t, func() {
+			before()
+			_, err := beans.ResolveE(ComponentType, "something")
+			ShouldBeTrue(errors.Is(err, beans.ErrBeanNotFound))
+
+			var beanErr *beans.BeanError
+			ShouldBeTrue(errors.As(err, &beanErr))
+			ShouldEqual("something", beanErr.Name)
+			ShouldEqual("dependency something not registered, unable to resolve", err.Error())
+		})
+		Convey("Ambiguous primary", t, func() {
+			before()
+			ShouldNotError(beans.Register((*IOther)(nil), "name1", &OtherImpl1{name: "name1"}))
+			ShouldNotError(beans.Register((*IOther)(nil), "name2", &OtherImpl1{name: "name2"}))
+			_, err := beans.PrimaryE((*IOther)(nil))
+			ShouldBeTrue(errors.Is(err, beans.ErrAmbiguousPrimary))
+			ShouldBeFalse(errors.Is(err, beans.ErrNoPrimary))
+		})
+		Convey("Constructor returned nil", t, func() {
+			before()
+			calls := 0
+			ShouldNotError(beans.RegisterFunc((*IOther)(nil), "nil", func() interface{} {
+				calls++
+				return nil
+			}, true))
+			_, err := beans.ResolveE((*IOther)(nil), "nil")
+			ShouldBeTrue(errors.Is(err, beans.ErrNilInstance))
+			ShouldEqual("IOther/nil: constructor returned nil", err.Error())
+
+			// Failed singletons are not cached
+			_, err = beans.ResolveE((*IOther)(nil), "nil")
+			ShouldBeTrue(errors.Is(err, beans.ErrNilInstance))
+			ShouldEqual(2, calls)
+		})
+		Convey("SetPrimary errors", t, func() {
+			before()
+			ShouldBeTrue(errors.Is(beans.SetPrimary(ComponentType, "something"), beans.ErrBeanNotFound))
+			ShouldBeTrue(errors.Is(beans.SetPrimary((*IOther)(nil), "something"), beans.ErrTypeNotRegistered))
+		})
+	})
+}
+
+func TestResolveErrorsAreLogged(t *testing.T) {
+	Convey("Testing Resolve reports errors to the logger", t, func() {
+		before()
+		var logged error
+		beans.LogCallbacks().SetErrorCallback(func(err error) {
+			logged = err
+		})
+		defer beans.LogCallbacks().SetErrorCallback(nil)
+
+		ShouldBeNil(beans.Primary((*IOther)(nil)))
+		ShouldBeTrue(errors.Is(logged, beans.ErrTypeNotRegistered))
+	})
+}
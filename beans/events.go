@@ -0,0 +1,130 @@
+package beans
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// IEvent is implemented by all the events published by a container. See beans.Subscribe
+type IEvent interface {
+	// Info returns the data shared by all the events.
+	Info() EventInfo
+}
+
+// EventInfo contains the data shared by all the container events.
+type EventInfo struct {
+	// Type is the bean type involved in the event, nil if the event is not related to a bean.
+	Type reflect.Type
+	// Name is the bean name involved in the event, empty if the event is not related to a specific bean.
+	Name string
+	// Time is the moment the event was published.
+	Time time.Time
+}
+
+// Info returns the data shared by all the events.
+func (e EventInfo) Info() EventInfo {
+	return e
+}
+
+// BeanRegistered is published when a bean is registered by a name that was not registered before.
+type BeanRegistered struct {
+	EventInfo
+}
+
+// BeanOverridden is published when a bean is registered by a name that was already registered, replacing the previous
+// registration. It requires Allow Overrides to be set to TRUE
+type BeanOverridden struct {
+	EventInfo
+}
+
+// SingletonCreated is published when the instance of a singleton is created.
+type SingletonCreated struct {
+	EventInfo
+	// Instance is the created singleton instance.
+	Instance interface{}
+}
+
+// PrimaryChanged is published when the primary bean of a type changes.
+type PrimaryChanged struct {
+	EventInfo
+	// Previous is the name of the previous primary bean, empty if no bean was set as primary.
+	Previous string
+}
+
+// ResolveFailed is published when a bean fails to be resolved, including the beans resolved as dependencies of other
+// beans.
+type ResolveFailed struct {
+	EventInfo
+	// Err is the cause of the failure.
+	Err error
+}
+
+// ContainersCleared is published when all the registered beans of the container are cleared.
+type ContainersCleared struct {
+	EventInfo
+}
+
+// ShutdownStarted is published when the container starts stopping its singletons.
+type ShutdownStarted struct {
+	EventInfo
+}
+
+type subscriber struct {
+	handler func(event IEvent)
+}
+
+// Subscribe adds a handler invoked with every event published by the container. See beans.Subscribe
+func (c *Container) Subscribe(handler func(event IEvent)) (unsubscribe func()) {
+	s := &subscriber{handler: handler}
+	c.eventMux.Lock()
+	c.subscribers = append(c.subscribers, s)
+	c.eventMux.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.eventMux.Lock()
+			defer c.eventMux.Unlock()
+			for i, current := range c.subscribers {
+				if current == s {
+					c.subscribers = append(c.subscribers[:i:i], c.subscribers[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+}
+
+// newEventInfo returns the data shared by all the events, timestamped with the current time.
+func newEventInfo(t reflect.Type, name string) EventInfo {
+	return EventInfo{Type: t, Name: name, Time: time.Now()}
+}
+
+// publish invokes the subscribers with the provided event. It must not be invoked while holding 'mux', so the
+// subscribers are free to use the container.
+func (c *Container) publish(event IEvent) {
+	c.eventMux.RLock()
+	subscribers := c.subscribers
+	c.eventMux.RUnlock()
+
+	for _, s := range subscribers {
+		c.notify(s, event)
+	}
+}
+
+func (c *Container) notify(s *subscriber, event IEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.log().Error(fmt.Errorf("the subscriber of the container events panicked handling '%T': %v", event, r))
+		}
+	}()
+	s.handler(event)
+}
+
+// resolveFailed publishes a ResolveFailed event and returns the error.
+func (c *Container) resolveFailed(t reflect.Type, name string, err error) error {
+	c.publish(ResolveFailed{EventInfo: newEventInfo(t, name), Err: err})
+	return err
+}
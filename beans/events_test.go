@@ -0,0 +1,77 @@
+package beans_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+func TestEvents(t *testing.T) {
+	Convey("Testing the container events", t, func() {
+		ref := (*IOther)(nil)
+		c := beans.NewContainer()
+		var events []beans.IEvent
+		unsubscribe := c.Subscribe(func(event beans.IEvent) {
+			events = append(events, event)
+		})
+		kinds := func() []string {
+			var ret []string
+			for _, e := range events {
+				ret = append(ret, fmt.Sprintf("%T:%s", e, e.Info().Name))
+			}
+			events = nil
+			return ret
+		}
+
+		Convey("Registrations, overrides and primary changes", t, func() {
+			start := time.Now()
+			ShouldNotError(c.Register(ref, "real", &OtherImpl1{name: "real"}))
+			ShouldNotError(c.Register(ref, "other", &OtherImpl1{name: "other"}))
+			ShouldNotError(c.SetPrimary(ref, "real"))
+			ShouldNotError(c.SetPrimary(ref, "other"))
+			ShouldNotError(c.SetPrimary(ref, "other", true))
+			c.SetAllowOverrides(true)
+			ShouldNotError(c.Register(ref, "real", &OtherImpl1{name: "mock"}))
+
+			ShouldBeFalse(events[0].Info().Time.Before(start))
+			ShouldEqual(reflect.TypeOf(ref).Elem(), events[0].Info().Type)
+			ShouldEqual("real", events[3].(beans.PrimaryChanged).Previous)
+			ShouldEqual([]string{
+				"beans.BeanRegistered:real",
+				"beans.BeanRegistered:other",
+				"beans.PrimaryChanged:real",
+				"beans.PrimaryChanged:other",
+				"beans.BeanOverridden:real",
+			}, kinds())
+		})
+		Convey("Singleton creation and resolution failures", t, func() {
+			c.Resolve(ref, "real")
+			c.Resolve(ref, "real")
+			_, err := c.ResolveE(ref, "missing")
+			ShouldError(err)
+
+			failed := events[1].(beans.ResolveFailed)
+			ShouldBeTrue(errors.Is(failed.Err, beans.ErrBeanNotFound))
+			ShouldEqual("mock", events[0].(beans.SingletonCreated).Instance.(IOther).Name())
+			ShouldEqual([]string{"beans.SingletonCreated:real", "beans.ResolveFailed:missing"}, kinds())
+		})
+		Convey("Clear and shutdown", t, func() {
+			ShouldNotError(c.Shutdown(context.Background()))
+			ShouldNotError(c.Clear())
+			ShouldEqual([]string{"beans.ShutdownStarted:", "beans.ContainersCleared:"}, kinds())
+		})
+		Convey("Unsubscribed handlers and panics", t, func() {
+			c.Subscribe(func(beans.IEvent) { panic("failing subscriber") })
+			unsubscribe()
+			unsubscribe()
+			ShouldNotError(c.Register(ref, "after", &OtherImpl1{}))
+			ShouldEqual(0, len(events))
+		})
+	})
+}
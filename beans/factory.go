@@ -1,46 +1,93 @@
 package beans
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // The beans package was forked from github.com/jucardi/go-beans
 
 type dependencyCollection struct {
-	primary   string
-	instances map[string]*instanceInfo
-	ctors     map[string]*constructorInfo
+	primary string
+	ctors   map[string]*constructorInfo
 }
 
 type constructorInfo struct {
-	ctor      func() interface{}
-	singleton bool
+	ctor     func(ctx context.Context) (interface{}, error)
+	scope    Scope
+	deps     []dependency
+	seq      uint64
+	priority *int
+	profiles []string
+
+	// conditions are evaluated when the container is finalized to decide whether the bean is registered.
+	conditions    []Condition
+	onMissingBean bool
+
+	// beforeInit and afterInit are invoked with every constructed instance before and after it is initialized, and
+	// they may replace the instance.
+	beforeInit func(instance interface{}) (interface{}, error)
+	afterInit  func(instance interface{}) (interface{}, error)
+
+	// overrides holds the registrations replaced by this one. concrete holds the type of the instances, known either
+	// from the registration or once an instance is constructed, and the construction stats are updated every time an
+	// instance is constructed. resolutions counts the times the bean was resolved. They are used to describe the bean
+	// without constructing it, see ListBeans
+	overrides         []BeanOverride
+	concrete          atomic.Pointer[reflect.Type]
+	constructions     atomic.Int64
+	lastConstruction  atomic.Int64
+	totalConstruction atomic.Int64
+	resolutions       atomic.Int64
+
+	// lock serializes the construction of the singleton instance, so the constructor of a singleton bean is invoked
+	// exactly once even when the bean is resolved concurrently from multiple goroutines.
+	lock     constructionLock
+	instance atomic.Pointer[instanceInfo]
 }
 
 type instanceInfo struct {
-	resolvedFirstTime bool
+	resolvedFirstTime sync.Once
 	instance          interface{}
 }
 
-var (
-	allowOverrides = false
-	dependencies = map[reflect.Type]*dependencyCollection{}
-)
+var defaultContainer = NewContainer()
+
+// Default returns the default container, used by all the package level functions of this package.
+func Default() *Container {
+	return defaultContainer
+}
 
 // Clear clears all registered dependencies. It requires Allow Overrides to be set to TRUE. Use this with caution, it was meant for testing purposes only.
 func Clear() error {
-	if !allowOverrides {
-		return errors.New("unable to clear beans while Allow Overrides is set to FALSE")
-	}
-	dependencies = map[reflect.Type]*dependencyCollection{}
-	return nil
+	return defaultContainer.Clear()
 }
 
 // SetAllowOverrides is normally used when testing. It allows a registered bean to be overwritten by another implementation, like a Mock
 func SetAllowOverrides(allow bool) {
-	allowOverrides = allow
+	defaultContainer.SetAllowOverrides(allow)
+}
+
+// SetActiveProfiles sets the active profiles, which determine the beans registered with the beans.Profiles option that
+// are visible. Beans which profiles do not match the active profiles behave as if they were not registered, and the
+// errors returned when resolving them explain why they were excluded.
+//
+// If the active profiles are not set, they are the ones read from the BEANS_PROFILES_ACTIVE environment variable as a
+// comma separated list, when the package is initialized.
+//
+//   Eg.   beans.SetActiveProfiles("dev", "local")
+//
+func SetActiveProfiles(profiles ...string) {
+	defaultContainer.SetActiveProfiles(profiles...)
+}
+
+// ActiveProfiles returns the active profiles, either set with SetActiveProfiles or read from the BEANS_PROFILES_ACTIVE
+// environment variable when the package is initialized.
+func ActiveProfiles() []string {
+	return defaultContainer.ActiveProfiles()
 }
 
 // Resolve resolves the bean type by the given name.
@@ -57,8 +104,81 @@ func SetAllowOverrides(allow bool) {
 //
 //   Eg.   bean.Resolve(reference, beanName)
 //
+// The name may contain '${key}' or '${key:default}' placeholders, which are replaced by the value of the property by
+// the given key (see Property), or by the default value if the property is not set. A placeholder which property is not
+// set and has no default value fails with ErrUnresolvedPlaceholder
+//
+//   Eg.   bean.Resolve((*IAlertHandler)(nil), "${alert.type:email}")
+//
 func Resolve(ref interface{}, name string) interface{} {
-	return Get(getType(ref), name)
+	return defaultContainer.Resolve(ref, name)
+}
+
+// ResolveE resolves the bean type by the given name, same as Resolve but returns an error if the bean cannot be
+// resolved rather than reporting it to the logger. The returned error is a *BeanError that wraps one of the sentinel
+// errors of this package (ErrTypeNotRegistered, ErrBeanNotFound, ErrNoPrimary, ErrAmbiguousPrimary or ErrNilInstance)
+//
+//   Eg.   svc, err := bean.ResolveE((*IService)(nil), beanName)
+//         if errors.Is(err, beans.ErrBeanNotFound) { ...
+//
+func ResolveE(ref interface{}, name string) (interface{}, error) {
+	return defaultContainer.ResolveE(ref, name)
+}
+
+// ResolveCtx resolves the bean type by the given name, same as Resolve but using the scope of the provided context to
+// resolve context scoped beans (see Scoped and NewScope). The context is also propagated to the constructors of the
+// beans, so context scoped beans may depend on other context scoped beans.
+//
+//   Eg.   ctx, cancel := beans.NewScope(r.Context())
+//         defer cancel()
+//         bean.ResolveCtx(ctx, (*IService)(nil), beanName)
+//
+func ResolveCtx(ctx context.Context, ref interface{}, name string) interface{} {
+	return defaultContainer.ResolveCtx(ctx, ref, name)
+}
+
+// ResolveCtxE resolves the bean type by the given name, same as ResolveCtx but returns an error if the bean cannot be
+// resolved rather than reporting it to the logger. A context scoped bean resolved without a scope context fails with
+// ErrNoScope
+func ResolveCtxE(ctx context.Context, ref interface{}, name string) (interface{}, error) {
+	return defaultContainer.ResolveCtxE(ctx, ref, name)
+}
+
+// ResolveAll resolves all the beans registered for the bean type. An empty list is returned if no beans are registered
+// for the type.
+//
+// The beans are sorted by the priority provided at their registration (see Priority), or by the order returned by the
+// beans that implement IOrdered, where lower values come first. Beans with no priority nor order come last, and ties
+// are broken by the registration order.
+//
+// Beans that fail to be resolved are reported in the returned *MultiError, while the beans that were successfully
+// resolved are still returned.
+//
+//   Eg.   handlers, err := bean.ResolveAll((*IAlertHandler)(nil))
+//         for _, h := range handlers {
+//             h.(IAlertHandler).Send(alert)
+//         }
+//
+func ResolveAll(ref interface{}) ([]interface{}, error) {
+	return defaultContainer.ResolveAll(ref)
+}
+
+// ResolveMap resolves all the beans registered for the bean type, indexed by their bean names. See ResolveAll
+func ResolveMap(ref interface{}) (map[string]interface{}, error) {
+	return defaultContainer.ResolveMap(ref)
+}
+
+// ResolveAllCtx resolves all the beans registered for the bean type, same as ResolveAll but using the scope of the
+// provided context to resolve context scoped beans. Context scoped beans resolved without a scope context are reported
+// with ErrNoScope. See ResolveCtx
+func ResolveAllCtx(ctx context.Context, ref interface{}) ([]interface{}, error) {
+	return defaultContainer.ResolveAllCtx(ctx, ref)
+}
+
+// ResolveMapCtx resolves all the beans registered for the bean type, indexed by their bean names, using the scope of
+// the provided context to resolve context scoped beans. See ResolveAllCtx
+func ResolveMapCtx(ctx context.Context, ref interface{}) (map[string]interface{}, error) {
+	return defaultContainer.ResolveMapCtx(ctx, ref)
 }
 
 // Primary resolves the bean type using the registered bean set as primary
@@ -76,63 +196,200 @@ func Resolve(ref interface{}, name string) interface{} {
 //   Eg.   bean.Primary(reference)
 //
 func Primary(ref interface{}) interface{} {
-	return GetPrimary(getType(ref))
+	return defaultContainer.Primary(ref)
+}
+
+// PrimaryE resolves the bean type using the registered bean set as primary, same as Primary but returns an error if
+// the bean cannot be resolved rather than reporting it to the logger. See ResolveE
+//
+//   Eg.   svc, err := bean.PrimaryE((*IService)(nil))
+//
+func PrimaryE(ref interface{}) (interface{}, error) {
+	return defaultContainer.PrimaryE(ref)
 }
 
 // InitComponents initializes all registered constructors for singleton components. This should be called after
 // any required configuration has been loaded.
-func InitComponents() {
-	log().Info("initializing singleton components")
-	for t, dep := range dependencies {
-		for name, ctor := range dep.ctors {
-			if _, ok := dep.instances[name]; ok || !ctor.singleton {
-				continue
-			}
-			log().Debug(fmt.Sprintf("component for type=%s, name=%s", t.String(), name))
-			dep.instances[name] = newInstanceInfo(ctor.ctor())
-		}
-	}
+//
+// The singletons are constructed following their dependencies, so a bean is always constructed after the beans it
+// depends on. The dependencies are obtained from the constructor parameters (see RegisterConstructor), from the bean
+// tags of auto injected beans (see AutoInject) and from the resolutions previously performed by the constructors.
+// Beans that do not depend on each other are constructed in the order they were registered. The container is
+// finalized before the singletons are initialized, see Finalize.
+//
+// Every bean that fails to be constructed is reported in the returned *MultiError, including constructors that
+// panic. All the singletons are attempted, use InitComponentsFailFast to stop the initialization on the first failure.
+//
+//   Eg.   if err := beans.InitComponents(); err != nil { ...
+//
+func InitComponents() error {
+	return defaultContainer.InitComponents()
+}
+
+// InitComponentsFailFast initializes all registered constructors for singleton components the same way as
+// InitComponents, but stops the initialization on the first failure.
+func InitComponentsFailFast() error {
+	return defaultContainer.InitComponentsFailFast()
+}
+
+// Finalize evaluates the conditions of the beans registered with conditional options (see Conditional,
+// ConditionalOnBean, ConditionalOnMissingBean and ConditionalOnProperty), registering the beans which conditions are
+// met. It is invoked by InitComponents, so it is only required when the container is used without initializing the
+// components. Conditional beans registered once the container is finalized are evaluated right away.
+//
+// Beans which conditions are met and which names are already registered are reported in the returned *MultiError
+// unless Allow Overrides is set to TRUE.
+//
+//   Eg.   beans.Register((*ICache)(nil), "memory", NewMemoryCache(), beans.ConditionalOnMissingBean((*ICache)(nil)))
+//         beans.Register((*ICache)(nil), "redis", NewRedisCache())
+//         beans.Finalize() // only the 'redis' cache is registered
+//
+func Finalize() error {
+	return defaultContainer.Finalize()
+}
+
+// SetProperty sets the value of a property, which takes precedence over the value set in the environment. Properties
+// are used by the ConditionalOnProperty option.
+func SetProperty(key, value string) {
+	defaultContainer.SetProperty(key, value)
+}
+
+// Property returns the value of a property. Properties set with SetProperty take precedence, followed by the property
+// sources in this order:
+//
+//   - Command line flags (see FlagProperties)
+//   - Environment variables, where the key is upper cased and '.' and '-' are replaced by '_', eg: 'smtp.host' is read
+//     from SMTP_HOST
+//   - JSON and YAML files (see JSONFile and YAMLFile), where files added later take precedence over files added first
+//
+//   Eg.   beans.Property("smtp.host")
+//
+func Property(key string) (string, bool) {
+	return defaultContainer.Property(key)
+}
+
+// AddPropertySource adds a source of properties, such as JSON or YAML files, or command line flags. Environment
+// variables are always used as a property source. See Property for the precedence of the property sources.
+//
+//   Eg.   src, err := beans.YAMLFile("config/application.yaml")
+//         if err != nil { ...
+//         beans.AddPropertySource(src)
+//         beans.AddPropertySource(beans.FlagProperties(nil))
+//
+func AddPropertySource(source *PropertySource) {
+	defaultContainer.AddPropertySource(source)
+}
+
+// Bind fills the fields of the target config struct tagged with `config:"..."` using the properties under the provided
+// prefix, converting the values to the field types. Besides strings, bools and numbers, durations (eg: '30s'), types
+// that implement encoding.TextUnmarshaler, lists and maps are supported. Lists and maps are read either from a single
+// property with comma separated values (eg: 'a,b' or 'a=1,b=2') or from nested properties (eg: 'servers.0' or
+// 'headers.token'). Nested structs tagged with `config:"..."` are bound using their tag as prefix.
+//
+// Properties that are not found take the value of the `default:"..."` tag if provided, otherwise the field is left
+// untouched. All the properties that fail to be converted are reported in the returned *MultiError
+//
+//   Eg.   type SmtpConfig struct {
+//             Host    string        `config:"host" default:"localhost"`
+//             Port    int           `config:"port" default:"25"`
+//             Timeout time.Duration `config:"timeout" default:"10s"`
+//             To      []string      `config:"to"`
+//         }
+//
+//         cfg := &SmtpConfig{}
+//         err := beans.Bind("smtp", cfg)
+//
+func Bind(prefix string, target interface{}) error {
+	return defaultContainer.Bind(prefix, target)
+}
+
+// RegisterConfig registers the target config struct as a singleton bean of its pointer type, so constructors may
+// depend on it. The config struct is bound with the properties under the provided prefix when the bean is constructed,
+// so the property sources may be added after the registration. See Bind
+//
+//   Eg.   beans.RegisterConfig("smtp", "smtp", &SmtpConfig{})
+//         beans.RegisterConstructor((*IMailer)(nil), "smtp", func(cfg *SmtpConfig) IMailer { ...
+//
+func RegisterConfig(name, prefix string, target interface{}, opts ...Option) error {
+	return defaultContainer.RegisterConfig(name, prefix, target, opts...)
+}
+
+// Start starts all the singletons created so far that implement IStarter, in the order they were created. It is
+// normally invoked after InitComponents, so all the singletons have been created. Singletons that were already started
+// are not started again, so Start may be invoked again to start singletons created later. The first error returned by
+// a starter stops the process and is returned.
+//
+//   Eg.   if err := beans.InitComponents(); err != nil { ...
+//         if err := beans.Start(ctx); err != nil { ...
+//
+func Start(ctx context.Context) error {
+	return defaultContainer.Start(ctx)
+}
+
+// Shutdown stops all the singletons created so far that implement IStopper or io.Closer, in the reverse order they
+// were created, so a bean is stopped before the beans it depends on. Every singleton is stopped at most once.
+//
+// Shutdown discards the created singletons, so resolving them afterwards constructs new instances, which are started
+// again by Start. Beans registered as an instance with Register are resolved again with that same instance.
+//
+// If the context is done before all the singletons are stopped, the remaining singletons are not stopped and are
+// reported in the returned error along with any error returned while stopping the singletons.
+//
+//   Eg.   ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//         defer cancel()
+//         if err := beans.Shutdown(ctx); err != nil { ...
+//
+func Shutdown(ctx context.Context) error {
+	return defaultContainer.Shutdown(ctx)
 }
 
 // Get gets the the instance by the specified name.
 func Get(t reflect.Type, name string) interface{} {
-	if !containsType(dependencies, t) {
-		log().Error(fmt.Errorf("no dependencies found for type %s, unable to resolve", t.Name()))
-		return nil
-	}
+	return defaultContainer.Get(t, name)
+}
 
-	if name == "" {
-		return GetPrimary(t)
-	}
+// GetE gets the the instance by the specified name, returning an error if the instance cannot be resolved.
+func GetE(t reflect.Type, name string) (interface{}, error) {
+	return defaultContainer.GetE(t, name)
+}
 
-	if instance, ok := dependencies[t].instances[name]; ok {
-		return triggerOnResolve(instance)
-	} else if ctorInfo, ok := dependencies[t].ctors[name]; ok {
-		instance = newInstanceInfo(ctorInfo.ctor())
-		if ctorInfo.singleton {
-			dependencies[t].instances[name] = instance
-		}
-		return triggerOnResolve(instance)
-	}
+// GetCtxE gets the the instance by the specified name, using the scope of the provided context to resolve context
+// scoped beans. See ResolveCtx
+func GetCtxE(ctx context.Context, t reflect.Type, name string) (interface{}, error) {
+	return defaultContainer.GetCtxE(ctx, t, name)
+}
 
-	log().Error(fmt.Errorf("dependency %s not registered, unable to resolve", name))
-	return nil
+// GetAll gets all the instances registered for the provided type. See ResolveAll
+func GetAll(t reflect.Type) ([]interface{}, error) {
+	return defaultContainer.GetAll(t)
+}
+
+// GetAllCtx gets all the instances registered for the provided type, using the scope of the provided context to
+// resolve context scoped beans. See ResolveAllCtx
+func GetAllCtx(ctx context.Context, t reflect.Type) ([]interface{}, error) {
+	return defaultContainer.GetAllCtx(ctx, t)
+}
+
+// GetMap gets all the instances registered for the provided type, indexed by their bean names. See ResolveAll
+func GetMap(t reflect.Type) (map[string]interface{}, error) {
+	return defaultContainer.GetMap(t)
+}
+
+// GetMapCtx gets all the instances registered for the provided type, indexed by their bean names, using the scope of
+// the provided context to resolve context scoped beans. See ResolveMapCtx
+func GetMapCtx(ctx context.Context, t reflect.Type) (map[string]interface{}, error) {
+	return defaultContainer.GetMapCtx(ctx, t)
 }
 
 // GetPrimary gets the primary dependency registered in this factory instance, same as primary but with a reflect.Type
 func GetPrimary(t reflect.Type) interface{} {
-	if dependencies[t].primary != "" {
-		return Get(t, dependencies[t].primary)
-	}
-
-	if len(dependencies[t].ctors) == 1 {
-		for name := range dependencies[t].ctors {
-			return Get(t, name)
-		}
-	}
+	return defaultContainer.GetPrimary(t)
+}
 
-	log().Error(fmt.Errorf("no primary dependency found for type '%s'", t.Name()))
-	return nil
+// GetPrimaryE gets the primary dependency registered in this factory instance, returning an error if the instance
+// cannot be resolved.
+func GetPrimaryE(t reflect.Type) (interface{}, error) {
+	return defaultContainer.GetPrimaryE(t)
 }
 
 // RegisterFuncByType registers a bean function retriever into the factory,
@@ -150,30 +407,7 @@ func GetPrimary(t reflect.Type) interface{} {
 //                  rather than on an init.
 //
 func RegisterFuncByType(t reflect.Type, name string, fn func() interface{}, singleton ...bool) error {
-	if name == "" {
-		return errors.New("the name cannot be empty")
-	}
-
-	if !containsType(dependencies, t) {
-		dependencies[t] = &dependencyCollection{
-			instances: map[string]*instanceInfo{},
-			ctors:     map[string]*constructorInfo{},
-		}
-	}
-
-	if _, ok := dependencies[t].ctors[name]; ok && !allowOverrides {
-		return fmt.Errorf("a dependency with name %s is already registered", name)
-	}
-	if _, ok := dependencies[t].instances[name]; ok {
-		delete(dependencies[t].instances, name)
-	}
-
-	dependencies[t].ctors[name] = &constructorInfo{
-		ctor:      fn,
-		singleton: len(singleton) > 0 && singleton[0],
-	}
-
-	return nil
+	return defaultContainer.RegisterFuncByType(t, name, fn, singleton...)
 }
 
 // RegisterFunc registers a bean function retriever into the factory.
@@ -204,16 +438,39 @@ func RegisterFuncByType(t reflect.Type, name string, fn func() interface{}, sing
 //                     rather than on an init.
 //
 func RegisterFunc(interfaceRef interface{}, name string, fn func() interface{}, singleton ...bool) error {
-	return RegisterFuncByType(getType(interfaceRef), name, fn, singleton...)
+	return defaultContainer.RegisterFunc(interfaceRef, name, fn, singleton...)
 }
 
-// RegisterByType registers a bean singleton instance into the factory.
-func RegisterByType(t reflect.Type, name string, component interface{}) error {
-	if ct := reflect.TypeOf(component); !ct.Implements(t) {
-		return fmt.Errorf("the component type '%s' does not implement the provided type '%s'", ct.Name(), t.Name())
-	}
+// RegisterConstructorByType registers a constructor function into the factory, which parameters are resolved from
+// the factory when the bean is constructed. See RegisterConstructor
+func RegisterConstructorByType(t reflect.Type, name string, ctor interface{}, opts ...Option) error {
+	return defaultContainer.RegisterConstructorByType(t, name, ctor, opts...)
+}
 
-	return RegisterFuncByType(t, name, func() interface{} { return component }, true)
+// RegisterConstructor registers a constructor function into the factory. Unlike RegisterFunc, the constructor can be
+// any function that returns the bean instance, optionally followed by an error. The parameters of the constructor are
+// inspected and each one of them is resolved from the factory when the bean is constructed, using the primary bean of
+// the parameter type unless a name is provided with the ParamNames option. An error returned by the constructor is
+// returned by ResolveE and GetE.
+//
+//   Eg.   func NewService(repo IRepo, log ILogger) (IService, error) { ...
+//
+//         bean.RegisterConstructor((*IService)(nil), beanName, NewService, beans.Singleton())
+//
+// Parameters:
+//
+//   {interfaceRef}  - The interface reference type for the bean
+//   {name}          - The name of the bean
+//   {ctor}          - A constructor function for the bean
+//   {opts}          - (Optional) registration options, such as Singleton and ParamNames
+//
+func RegisterConstructor(interfaceRef interface{}, name string, ctor interface{}, opts ...Option) error {
+	return defaultContainer.RegisterConstructor(interfaceRef, name, ctor, opts...)
+}
+
+// RegisterByType registers a bean singleton instance into the factory.
+func RegisterByType(t reflect.Type, name string, component interface{}, opts ...Option) error {
+	return defaultContainer.RegisterByType(t, name, component, opts...)
 }
 
 // Register registers a bean singleton instance into the factory.
@@ -230,24 +487,127 @@ func RegisterByType(t reflect.Type, name string, component interface{}) error {
 //
 //   Eg.   bean.Register(reference, beanName, instance)
 //
-func Register(interfaceRef interface{}, name string, component interface{}) error {
-	return RegisterByType(getType(interfaceRef), name, component)
+// The AutoInject option may be provided so the fields of the instance tagged with `bean:"..."` are injected when the
+// bean is resolved for the first time. See Inject
+//
+//   Eg.   bean.Register((*IService)(nil), beanName, &ServiceImpl{}, beans.AutoInject())
+//
+func Register(interfaceRef interface{}, name string, component interface{}, opts ...Option) error {
+	return defaultContainer.Register(interfaceRef, name, component, opts...)
 }
 
-// SetPrimaryByType sets the primary bean name to be used.
-func SetPrimaryByType(t reflect.Type, name string, replace ...bool) error {
-	if !containsType(dependencies, t) {
-		return fmt.Errorf("no dependencies found for type %s, unable to resolve", t.Name())
-	}
+// Inject fills the exported fields of the target struct tagged with `bean:"..."` with beans resolved from the factory.
+// The target must be a non-nil pointer to a struct.
+//
+// The tag value is the name of the bean to be injected, where an empty name means the primary bean of the field type.
+// The name can be followed by comma separated modifiers:
+//
+//   optional  - The field is left untouched if no bean is registered for it, rather than failing.
+//   primary   - The primary bean is injected if no bean is registered by the given name.
+//
+//   Eg.   type AlertService struct {
+//             Repo   IRepo         `bean:""`
+//             Mailer IAlertHandler `bean:"email"`
+//             Cache  ICache        `bean:",optional"`
+//             Alerts IAlertHandler `bean:"${alert.type:email}"`
+//         }
+//
+// The name may contain property placeholders, see Resolve.
+//
+// All the fields that cannot be injected are reported in a single *MultiError
+func Inject(target interface{}) error {
+	return defaultContainer.Inject(target)
+}
 
-	if _, ok := dependencies[t].ctors[name]; ok {
-		if dependencies[t].primary == "" || (dependencies[t].primary != "" && len(replace) > 0 && replace[0]) {
-			dependencies[t].primary = name
-		}
-		return nil
-	}
+// InjectCtx fills the exported fields of the target struct tagged with `bean:"..."`, same as Inject but using the scope
+// of the provided context to resolve context scoped beans. See ResolveCtx
+func InjectCtx(ctx context.Context, target interface{}) error {
+	return defaultContainer.InjectCtx(ctx, target)
+}
+
+// DecorateByType registers a decorator for all the beans of the provided type. See Decorate
+func DecorateByType(t reflect.Type, decorator Decorator) error {
+	return defaultContainer.DecorateByType(t, decorator)
+}
+
+// Decorate registers a decorator for all the beans of the referenced type, which wraps every instance constructed for
+// the type, such as caching, metrics or retry wrappers around beans registered by third party packages. The instance
+// returned by the decorator is used instead of the constructed instance, and it must implement the bean type.
+//
+// Decorators are applied once the instance is constructed and initialized, so singletons are decorated once and
+// prototypes are decorated on each construction. Singletons constructed before the decorator is registered are not
+// decorated. Multiple decorators of the same type are applied in the order they were registered, so the decorator
+// registered last is the outermost one.
+//
+//   Eg.   beans.Decorate((*IRepo)(nil), func(inner interface{}) interface{} {
+//             return &cachingRepo{inner: inner.(IRepo)}
+//         })
+//
+func Decorate(interfaceRef interface{}, decorator Decorator) error {
+	return defaultContainer.Decorate(interfaceRef, decorator)
+}
 
-	return fmt.Errorf("dependency %s not registered, unable to set as primary", name)
+// AddPostProcessor adds a post-processor, which hooks are invoked for every bean constructed by the container, before
+// and after the bean is initialized (see IBeanPostProcessor). Post-processors may replace the instances they receive,
+// and the errors they return fail the construction of the beans. It is meant for cross-cutting concerns such as
+// validation, auditing or registering the beans in a metrics system.
+//
+// Post-processors are invoked in the order provided by the Priority option or the IOrdered contract, where lower values
+// come first. Post-processors with no order come last, in the order they were added. Beans constructed before the
+// post-processor is added are not processed. The decorators of a bean (see Decorate) are applied after the
+// post-processors.
+//
+//   Eg.   beans.AddPostProcessor(&validationProcessor{}, beans.Priority(1))
+//
+func AddPostProcessor(processor IBeanPostProcessor, opts ...Option) error {
+	return defaultContainer.AddPostProcessor(processor, opts...)
+}
+
+// Subscribe adds a handler invoked with every event published by the default container, and returns a function that
+// removes the subscription. The handler receives one of the event structs of this package (BeanRegistered,
+// BeanOverridden, SingletonCreated, PrimaryChanged, ResolveFailed, ContainersCleared or ShutdownStarted), which carry
+// the bean type and name involved, and the time of the event.
+//
+// Handlers are invoked synchronously by the goroutine that triggered the event, so they should return quickly. They
+// are free to use the container, and a handler that panics is reported to the logger without affecting the container.
+//
+//   Eg.   unsubscribe := beans.Subscribe(func(event beans.IEvent) {
+//             if e, ok := event.(beans.BeanOverridden); ok {
+//                 log.Printf("bean %s overridden at %s", e.Name, e.Time)
+//             }
+//         })
+//         defer unsubscribe()
+//
+func Subscribe(handler func(event IEvent)) (unsubscribe func()) {
+	return defaultContainer.Subscribe(handler)
+}
+
+// ListBeans returns a descriptor for every registered bean, in the order they were registered, which is useful for
+// startup logging and admin tooling. Listing the beans never constructs any of them.
+//
+//   Eg.   for _, b := range beans.ListBeans() {
+//             log.Printf("%s/%s primary=%t scope=%s instantiated=%t", b.Type, b.Name, b.Primary, b.Scope, b.Instantiated)
+//         }
+//
+func ListBeans() []BeanDescriptor {
+	return defaultContainer.ListBeans()
+}
+
+// DependencyGraph returns the dependency graph of the beans, which can be exported as Graphviz DOT, as a Mermaid
+// flowchart or as JSON. The dependencies are obtained from the constructor parameters (see RegisterConstructor), from
+// the bean tags of auto injected beans (see AutoInject) and from the beans resolved by the constructors of other beans
+// (only known once the beans are constructed). Only the beans active for the active profiles are included, and
+// building the graph never constructs any bean.
+//
+//   Eg.   os.WriteFile("beans.dot", []byte(beans.DependencyGraph().DOT()), 0644)
+//
+func DependencyGraph() *Graph {
+	return defaultContainer.DependencyGraph()
+}
+
+// SetPrimaryByType sets the primary bean name to be used.
+func SetPrimaryByType(t reflect.Type, name string, replace ...bool) error {
+	return defaultContainer.SetPrimaryByType(t, name, replace...)
 }
 
 // SetPrimary sets the primary bean name to be used.
@@ -264,16 +624,17 @@ func SetPrimaryByType(t reflect.Type, name string, replace ...bool) error {
 //
 //   Eg.   bean.SetPrimary(reference, beanName)
 //
+// The name may contain property placeholders, see Resolve
+//
+//   Eg.   bean.SetPrimary((*IAlertHandler)(nil), "${alert.type:email}")
+//
 func SetPrimary(interfaceRef interface{}, name string, replace ...bool) error {
-	return SetPrimaryByType(getType(interfaceRef), name, replace...)
+	return defaultContainer.SetPrimary(interfaceRef, name, replace...)
 }
 
 // GetPrimaryNameByType returns the name of the primary bean. Returns an empty string if no beans exist as primary.
 func GetPrimaryNameByType(t reflect.Type) string {
-	if v, ok := dependencies[t]; ok {
-		return v.primary
-	}
-	return ""
+	return defaultContainer.GetPrimaryNameByType(t)
 }
 
 // GetPrimaryName returns the name of the primary bean. Returns an empty string if no beans exist as primary.
@@ -291,17 +652,12 @@ func GetPrimaryNameByType(t reflect.Type) string {
 //   Eg.   bean.GetPrimaryName(reference)
 //
 func GetPrimaryName(interfaceRef interface{}) string {
-	return GetPrimaryNameByType(getType(interfaceRef))
+	return defaultContainer.GetPrimaryName(interfaceRef)
 }
 
 // ExistsByType indicates if a dependency by the given name exists
 func ExistsByType(t reflect.Type, name string) bool {
-	if !containsType(dependencies, t) {
-		return false
-	}
-
-	_, ok := dependencies[t].ctors[name]
-	return ok
+	return defaultContainer.ExistsByType(t, name)
 }
 
 // Exists indicates if a dependency by the given name exists
@@ -319,7 +675,7 @@ func ExistsByType(t reflect.Type, name string) bool {
 //   Eg.   bean.Exists(reference, name)
 //
 func Exists(interfaceRef interface{}, name string) bool {
-	return ExistsByType(getType(interfaceRef), name)
+	return defaultContainer.Exists(interfaceRef, name)
 }
 
 func containsType(c map[reflect.Type]*dependencyCollection, key reflect.Type) bool {
@@ -338,3 +694,57 @@ func newInstanceInfo(instance interface{}) *instanceInfo {
 	return &instanceInfo{instance: instance}
 }
 
+func (c *constructorInfo) construct(ctx context.Context) (*instanceInfo, error) {
+	start := time.Now()
+	instance, err := c.ctor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, ErrNilInstance
+	}
+	if c.beforeInit != nil {
+		if instance, err = c.beforeInit(instance); err != nil {
+			return nil, err
+		}
+	}
+	if i, ok := instance.(IInitializer); ok {
+		if err := i.Init(); err != nil {
+			return nil, err
+		}
+	}
+	if c.afterInit != nil {
+		if instance, err = c.afterInit(instance); err != nil {
+			return nil, err
+		}
+	}
+	elapsed := int64(time.Since(start))
+	c.lastConstruction.Store(elapsed)
+	c.totalConstruction.Add(elapsed)
+	c.constructions.Add(1)
+	c.setConcreteType(reflect.TypeOf(instance))
+	return newInstanceInfo(instance), nil
+}
+
+func (c *constructorInfo) setConcreteType(t reflect.Type) {
+	if t != nil && t.Kind() != reflect.Interface {
+		c.concrete.Store(&t)
+	}
+}
+
+func (c *constructorInfo) concreteType() reflect.Type {
+	if t := c.concrete.Load(); t != nil {
+		return *t
+	}
+	return nil
+}
+
+func (c *constructorInfo) isInstantiated() bool {
+	return c.instance.Load() != nil
+}
+
+// needsConstruction indicates whether resolving the bean invokes its constructor.
+func (c *constructorInfo) needsConstruction() bool {
+	return c.scope != ScopeSingleton || !c.isInstantiated()
+}
+
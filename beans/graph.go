@@ -0,0 +1,176 @@
+package beans
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Graph kinds of edges.
+const (
+	// EdgeDeclared is an edge declared by a constructor parameter or by a `bean:"..."` tag of an auto injected bean.
+	EdgeDeclared = "declared"
+	// EdgeResolved is an edge recorded from a bean resolved by the constructor of another bean, with the context
+	// received by the constructor.
+	EdgeResolved = "resolved"
+)
+
+// Graph is the dependency graph of the beans of a container. See beans.DependencyGraph
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a bean of the dependency graph.
+type GraphNode struct {
+	// ID identifies the bean as 'Type/name'
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Scope   string `json:"scope"`
+	Primary bool   `json:"primary"`
+}
+
+// GraphEdge is a dependency between two beans of the graph, where the 'From' bean depends on the 'To' bean.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Kind is either EdgeDeclared or EdgeResolved
+	Kind string `json:"kind"`
+}
+
+// DependencyGraph returns the dependency graph of the beans active for the active profiles. See beans.DependencyGraph
+func (c *Container) DependencyGraph() *Graph {
+	active := c.activeProfiles()
+	type entry struct {
+		dependency
+		ctor *constructorInfo
+	}
+
+	c.mux.RLock()
+	var entries []entry
+	primaries := map[reflect.Type]string{}
+	for t, dep := range c.dependencies {
+		primaries[t] = c.primaryNameOf(dep, active)
+		for name, ctor := range dep.ctors {
+			if ctor.isActive(active) {
+				entries = append(entries, entry{dependency: dependency{t: t, name: name}, ctor: ctor})
+			}
+		}
+	}
+	c.mux.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ctor.seq < entries[j].ctor.seq
+	})
+
+	g := &Graph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	nodes := map[dependency]bool{}
+	for _, e := range entries {
+		nodes[e.dependency] = true
+		g.Nodes = append(g.Nodes, GraphNode{
+			ID:      nodeID(e.dependency),
+			Type:    e.t.String(),
+			Name:    e.name,
+			Scope:   e.ctor.scope.String(),
+			Primary: primaries[e.t] == e.name,
+		})
+	}
+
+	// A dependency between two beans is reported once, as declared if it is both declared and resolved.
+	seen := map[[2]dependency]bool{}
+	addEdge := func(from, to dependency, kind string) {
+		if to.name = c.expandNameOrKeep(to.t, to.name); to.name == "" {
+			to.name = primaries[to.t]
+		}
+		if !nodes[to] || seen[[2]dependency{from, to}] {
+			return
+		}
+		seen[[2]dependency{from, to}] = true
+		g.Edges = append(g.Edges, GraphEdge{From: nodeID(from), To: nodeID(to), Kind: kind})
+	}
+
+	discovered := map[dependency][]dependency{}
+	c.buildMux.Lock()
+	for _, e := range entries {
+		discovered[e.dependency] = append([]dependency{}, c.discovered[e.dependency]...)
+	}
+	c.buildMux.Unlock()
+
+	for _, e := range entries {
+		for _, d := range e.ctor.deps {
+			addEdge(e.dependency, d, EdgeDeclared)
+		}
+	}
+	for _, e := range entries {
+		for _, d := range discovered[e.dependency] {
+			addEdge(e.dependency, d, EdgeResolved)
+		}
+	}
+	return g
+}
+
+// DOT returns the graph in the Graphviz DOT format. Primary beans are filled.
+func (g *Graph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph beans {\n")
+	sb.WriteString("  node [shape=box];\n")
+	for _, n := range g.Nodes {
+		attrs := fmt.Sprintf("label=%q", n.Type+"\n"+n.Name+"\n("+n.Scope+")")
+		if n.Primary {
+			attrs += ", style=\"filled,bold\", fillcolor=\"lightblue\""
+		}
+		sb.WriteString(fmt.Sprintf("  %q [%s];\n", n.ID, attrs))
+	}
+	for _, e := range g.Edges {
+		attrs := ""
+		if e.Kind == EdgeResolved {
+			attrs = " [style=dashed]"
+		}
+		sb.WriteString(fmt.Sprintf("  %q -> %q%s;\n", e.From, e.To, attrs))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Mermaid returns the graph as a Mermaid flowchart. Primary beans use the 'primary' class.
+func (g *Graph) Mermaid() string {
+	ids := map[string]string{}
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+		label := strings.ReplaceAll(n.Type+"<br/>"+n.Name+"<br/>("+n.Scope+")", `"`, "#quot;")
+		sb.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", ids[n.ID], label))
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Kind == EdgeResolved {
+			arrow = "-.->"
+		}
+		sb.WriteString(fmt.Sprintf("  %s %s %s\n", ids[e.From], arrow, ids[e.To]))
+	}
+
+	var primaries []string
+	for _, n := range g.Nodes {
+		if n.Primary {
+			primaries = append(primaries, ids[n.ID])
+		}
+	}
+	if len(primaries) > 0 {
+		sb.WriteString("  classDef primary fill:#add8e6,stroke-width:2px\n")
+		sb.WriteString(fmt.Sprintf("  class %s primary\n", strings.Join(primaries, ",")))
+	}
+	return sb.String()
+}
+
+// JSON returns the graph encoded as JSON.
+func (g *Graph) JSON() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+func nodeID(dep dependency) string {
+	return dep.t.String() + "/" + dep.name
+}
@@ -0,0 +1,80 @@
+package beans_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	Convey("Testing the dependency graph export", t, func() {
+		ref := (*IOther)(nil)
+		c := beans.NewContainer()
+		ShouldNotError(c.Register(ref, "db", &OtherImpl1{name: "db"}))
+		ShouldNotError(c.RegisterConstructor(ComponentType, "composed", func(other IOther) IService {
+			return &composedService{other: other}
+		}, beans.Singleton()))
+		ShouldNotError(c.RegisterConstructor(ref, "lazy", func(ctx context.Context) IOther {
+			return &OtherImpl1{name: c.ResolveCtx(ctx, ref, "db").(IOther).Name()}
+		}))
+		ShouldNotError(c.SetPrimary(ref, "db"))
+		c.Resolve(ref, "lazy")
+
+		g := c.DependencyGraph()
+
+		Convey("Nodes and edges come from constructors and resolutions", t, func() {
+			ShouldEqual(3, len(g.Nodes))
+			ShouldEqual(beans.GraphNode{ID: "beans_test.IOther/db", Type: "beans_test.IOther", Name: "db", Scope: "singleton", Primary: true}, g.Nodes[0])
+			ShouldEqual(beans.GraphNode{ID: "beans_test.IService/composed", Type: "beans_test.IService", Name: "composed", Scope: "singleton", Primary: true}, g.Nodes[1])
+			ShouldBeFalse(g.Nodes[2].Primary)
+			ShouldEqual("prototype", g.Nodes[2].Scope)
+			ShouldEqual([]beans.GraphEdge{
+				{From: "beans_test.IService/composed", To: "beans_test.IOther/db", Kind: beans.EdgeDeclared},
+				{From: "beans_test.IOther/lazy", To: "beans_test.IOther/db", Kind: beans.EdgeResolved},
+			}, g.Edges)
+		})
+		Convey("The graph is exported as DOT", t, func() {
+			dot := g.DOT()
+			ShouldContain(dot, "digraph beans {")
+			ShouldContain(dot, `"beans_test.IOther/db" [label="beans_test.IOther\ndb\n(singleton)", style="filled,bold", fillcolor="lightblue"];`)
+			ShouldContain(dot, `"beans_test.IOther/lazy" [label="beans_test.IOther\nlazy\n(prototype)"];`)
+			ShouldContain(dot, `"beans_test.IOther/lazy" -> "beans_test.IOther/db" [style=dashed];`)
+		})
+		Convey("The graph is exported as Mermaid", t, func() {
+			mermaid := g.Mermaid()
+			ShouldContain(mermaid, "flowchart LR\n")
+			ShouldContain(mermaid, `n0["beans_test.IOther<br/>db<br/>(singleton)"]`)
+			ShouldContain(mermaid, "n1 --> n0")
+			ShouldContain(mermaid, "n2 -.-> n0")
+			ShouldContain(mermaid, "class n0,n1 primary")
+		})
+		Convey("The graph is exported as JSON", t, func() {
+			data, err := g.JSON()
+			ShouldNotError(err)
+			decoded := &beans.Graph{}
+			ShouldNotError(json.Unmarshal(data, decoded))
+			ShouldEqual(g, decoded)
+		})
+	})
+}
+
+func TestDependencyGraphResolvedEdges(t *testing.T) {
+	Convey("Testing the resolved edges of dependencies constructed before their consumers", t, func() {
+		ref := (*IOther)(nil)
+		c := beans.NewContainer()
+		ShouldNotError(c.RegisterConstructor(ref, "db", func() IOther {
+			return &OtherImpl1{name: "db"}
+		}, beans.Singleton()))
+		ShouldNotError(c.RegisterConstructor(ComponentType, "consumer", func(ctx context.Context) IService {
+			return &composedService{other: c.ResolveCtx(ctx, ref, "db").(IOther)}
+		}, beans.Singleton()))
+		ShouldNotError(c.InitComponents())
+
+		ShouldEqual([]beans.GraphEdge{
+			{From: "beans_test.IService/consumer", To: "beans_test.IOther/db", Kind: beans.EdgeResolved},
+		}, c.DependencyGraph().Edges)
+	})
+}
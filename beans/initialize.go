@@ -0,0 +1,143 @@
+package beans
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ErrConstructorPanic indicates that the constructor of a bean panicked while initializing the components.
+var ErrConstructorPanic = errors.New("constructor panicked")
+
+type beanNode struct {
+	dependency
+	ctor *constructorInfo
+	deps []dependency
+}
+
+// InitComponents finalizes the container and initializes all registered constructors for singleton components,
+// following the order of their dependencies. See beans.InitComponents
+func (c *Container) InitComponents() error {
+	return c.initComponents(false)
+}
+
+// InitComponentsFailFast is the same as InitComponents, but stops the initialization on the first failure. See
+// beans.InitComponentsFailFast
+func (c *Container) InitComponentsFailFast() error {
+	return c.initComponents(true)
+}
+
+func (c *Container) initComponents(failFast bool) error {
+	c.log().Info("initializing singleton components")
+
+	errs := c.finalize()
+	for _, node := range c.initializationOrder() {
+		if len(errs) > 0 && failFast {
+			break
+		}
+		if node.ctor.scope != ScopeSingleton || node.ctor.isInstantiated() {
+			continue
+		}
+		c.log().Debug(fmt.Sprintf("component for type=%s, name=%s", node.t.String(), node.name))
+		if err := c.initComponent(node.dependency); err != nil {
+			c.log().Error(err)
+			errs = append(errs, err)
+		}
+	}
+
+	return newMultiError(fmt.Sprintf("unable to initialize %d component(s)", len(errs)), errs)
+}
+
+func (c *Container) initComponent(dep dependency) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = c.resolveFailed(dep.t, dep.name, &BeanError{Type: dep.t, Name: dep.name, Err: fmt.Errorf("%w: %v", ErrConstructorPanic, r)})
+		}
+	}()
+
+	if _, _, err = c.instanceOf(context.Background(), dep.t, dep.name); err != nil {
+		return c.resolveFailed(dep.t, dep.name, err)
+	}
+	return nil
+}
+
+// initializationOrder returns the registered beans enabled by the current profiles, sorted topologically by their known
+// dependencies so every bean comes after the beans it depends on. Ties are broken by the registration order.
+// Dependency cycles are not reported here, they are reported when the beans are constructed.
+func (c *Container) initializationOrder() []*beanNode {
+	active := c.activeProfiles()
+	c.mux.RLock()
+	nodes := map[dependency]*beanNode{}
+	var sorted []*beanNode
+	for t, dep := range c.dependencies {
+		for name, ctor := range dep.ctors {
+			if !ctor.isActive(active) {
+				continue
+			}
+			node := &beanNode{dependency: dependency{t: t, name: name}, ctor: ctor, deps: ctor.deps}
+			nodes[node.dependency] = node
+			sorted = append(sorted, node)
+		}
+	}
+	primaries := map[reflect.Type]string{}
+	for t, dep := range c.dependencies {
+		primaries[t] = c.primaryNameOf(dep, active)
+	}
+	c.mux.RUnlock()
+
+	c.buildMux.Lock()
+	for _, node := range sorted {
+		node.deps = append(append([]dependency{}, node.deps...), c.discovered[node.dependency]...)
+	}
+	c.buildMux.Unlock()
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ctor.seq < sorted[j].ctor.seq
+	})
+
+	// Kahn's algorithm, where the bean registered first is picked among all the beans which dependencies are satisfied.
+	pending := map[*beanNode]int{}
+	dependents := map[*beanNode][]*beanNode{}
+	for _, node := range sorted {
+		seen := map[*beanNode]bool{}
+		for _, d := range node.deps {
+			if d.name = c.expandNameOrKeep(d.t, d.name); d.name == "" {
+				d.name = primaries[d.t]
+			}
+			if target, ok := nodes[d]; ok && target != node && !seen[target] {
+				seen[target] = true
+				pending[node]++
+				dependents[target] = append(dependents[target], node)
+			}
+		}
+	}
+
+	ret := make([]*beanNode, 0, len(sorted))
+	done := map[*beanNode]bool{}
+	for len(ret) < len(sorted) {
+		var next *beanNode
+		for _, node := range sorted {
+			if !done[node] && pending[node] == 0 {
+				next = node
+				break
+			}
+		}
+		if next == nil {
+			// Only dependency cycles remain, which are reported when the beans are constructed.
+			for _, node := range sorted {
+				if !done[node] {
+					ret = append(ret, node)
+				}
+			}
+			break
+		}
+		done[next] = true
+		ret = append(ret, next)
+		for _, dependent := range dependents[next] {
+			pending[dependent]--
+		}
+	}
+	return ret
+}
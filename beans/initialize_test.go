@@ -0,0 +1,79 @@
+package beans_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+func TestInitComponents(t *testing.T) {
+	Convey("Testing InitComponents", t, func() {
+		Convey("Singletons are constructed after their dependencies", t, func() {
+			c := beans.NewContainer()
+			var order []string
+			ShouldNotError(c.RegisterConstructor(ComponentType, "service", func(other IOther) IService {
+				order = append(order, "service")
+				return &composedService{other: other}
+			}, beans.Singleton(), beans.ParamNames("second")))
+			ShouldNotError(c.RegisterFunc((*IOther)(nil), "first", func() interface{} {
+				order = append(order, "first")
+				return &OtherImpl1{name: "first"}
+			}, true))
+			ShouldNotError(c.RegisterConstructor((*IOther)(nil), "second", func() IOther {
+				order = append(order, "second")
+				return &OtherImpl1{name: "second"}
+			}, beans.Singleton()))
+			ShouldNotError(c.RegisterFunc((*IOther)(nil), "prototype", func() interface{} {
+				order = append(order, "prototype")
+				return &OtherImpl1{name: "prototype"}
+			}))
+
+			ShouldNotError(c.InitComponents())
+			ShouldEqual([]string{"first", "second", "service"}, order)
+		})
+		Convey("All failures are aggregated", t, func() {
+			c := beans.NewContainer()
+			built := false
+			ShouldNotError(c.RegisterConstructor(ComponentType, "failing", func() (IService, error) {
+				return nil, errors.New("some error")
+			}, beans.Singleton()))
+			ShouldNotError(c.RegisterFunc(ComponentType, "panicking", func() interface{} {
+				panic("some panic")
+			}, true))
+			ShouldNotError(c.RegisterFunc(ComponentType, "working", func() interface{} {
+				built = true
+				return &TestServiceImpl2{}
+			}, true))
+
+			err := c.InitComponents()
+			var multi *beans.MultiError
+			ShouldBeTrue(errors.As(err, &multi))
+			ShouldEqual(2, len(multi.Errors))
+			ShouldBeTrue(errors.Is(err, beans.ErrConstructorPanic))
+			ShouldBeTrue(built)
+
+			var beanErr *beans.BeanError
+			ShouldBeTrue(errors.As(multi.Errors[0], &beanErr))
+			ShouldEqual("failing", beanErr.Name)
+		})
+		Convey("Fail fast stops on the first failure", t, func() {
+			c := beans.NewContainer()
+			built := false
+			ShouldNotError(c.RegisterFunc(ComponentType, "panicking", func() interface{} {
+				panic("some panic")
+			}, true))
+			ShouldNotError(c.RegisterFunc(ComponentType, "working", func() interface{} {
+				built = true
+				return &TestServiceImpl2{}
+			}, true))
+
+			err := c.InitComponentsFailFast()
+			var multi *beans.MultiError
+			ShouldBeTrue(errors.As(err, &multi))
+			ShouldEqual(1, len(multi.Errors))
+			ShouldBeFalse(built)
+		})
+	})
+}
@@ -0,0 +1,153 @@
+package beans
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const beanTag = "bean"
+
+// injectionPoint describes a struct field tagged to be injected with a bean.
+//
+// The tag value is the name of the bean, optionally followed by comma separated modifiers:
+//
+//	Repo   IRepo         `bean:""`                 // the primary IRepo bean
+//	Mailer IAlertHandler `bean:"email"`            // the IAlertHandler bean named 'email'
+//	Cache  ICache        `bean:",optional"`        // left untouched if no ICache bean is registered
+//	Sms    IAlertHandler `bean:"sms,primary"`      // the 'sms' bean, or the primary bean if 'sms' is not registered
+//
+// Fields of the types Provider[T] and Lazy[T] are injected with a handle to the bean, resolved when it is used. The
+// modifiers do not apply to handles, since the bean is not resolved at injection time.
+type injectionPoint struct {
+	dependency
+	field    reflect.StructField
+	optional bool
+	primary  bool
+}
+
+// Inject fills the exported fields of the target struct tagged with `bean:"..."` with beans resolved from this
+// container. See beans.Inject
+func (c *Container) Inject(target interface{}) error {
+	return c.inject(context.Background(), target)
+}
+
+// InjectCtx fills the exported fields of the target struct tagged with `bean:"..."` with beans resolved from this
+// container, using the scope of the provided context to resolve context scoped beans. See beans.Inject
+func (c *Container) InjectCtx(ctx context.Context, target interface{}) error {
+	return c.inject(ctx, target)
+}
+
+func (c *Container) inject(ctx context.Context, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if target == nil || v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("the injection target must be a non-nil pointer to a struct, got '%T'", target)
+	}
+
+	points, err := injectionPoints(v.Elem().Type())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, p := range points {
+		if isHandle(p.t) {
+			v.Elem().FieldByIndex(p.field.Index).Set(c.newHandle(ctx, p.t, p.name))
+			continue
+		}
+		val, err := c.getE(ctx, p.t, p.name)
+		if err != nil && p.primary && p.name != "" && errors.Is(err, ErrBeanNotFound) {
+			val, err = c.getPrimaryE(ctx, p.t)
+		}
+		if err != nil {
+			if p.optional && isNotRegistered(err) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("unable to inject field '%s' (%s): %w", p.field.Name, beanKey(p.t, p.name), err))
+			continue
+		}
+
+		arg := valueOf(val, p.t)
+		if !arg.Type().AssignableTo(p.t) {
+			errs = append(errs, fmt.Errorf("unable to inject field '%s' (%s): the resolved bean of type '%s' is not assignable", p.field.Name, beanKey(p.t, p.name), arg.Type().String()))
+			continue
+		}
+		v.Elem().FieldByIndex(p.field.Index).Set(arg)
+	}
+
+	return newMultiError(fmt.Sprintf("unable to inject %d field(s) into '%T'", len(errs), target), errs)
+}
+
+// injectionPoints returns the fields of the provided struct type that are tagged to be injected.
+func injectionPoints(t reflect.Type) ([]injectionPoint, error) {
+	var ret []injectionPoint
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup(beanTag)
+		if !ok {
+			continue
+		}
+		if field.PkgPath != "" {
+			return nil, fmt.Errorf("the field '%s' of '%s' is tagged to be injected but it is not exported", field.Name, t.String())
+		}
+
+		parts := strings.Split(tag, ",")
+		p := injectionPoint{
+			dependency: dependency{t: field.Type, name: strings.TrimSpace(parts[0])},
+			field:      field,
+		}
+		for _, modifier := range parts[1:] {
+			switch strings.TrimSpace(modifier) {
+			case "optional":
+				p.optional = true
+			case "primary":
+				p.primary = true
+			case "":
+			default:
+				return nil, fmt.Errorf("unknown modifier '%s' in the bean tag of the field '%s' of '%s'", modifier, field.Name, t.String())
+			}
+		}
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+// injectionDependencies returns the dependencies declared by the bean tags of the provided type, if it is a pointer
+// to a struct.
+func injectionDependencies(t reflect.Type) []dependency {
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	points, _ := injectionPoints(t.Elem())
+	var ret []dependency
+	for _, p := range points {
+		if !isHandle(p.t) {
+			ret = append(ret, p.dependency)
+		}
+	}
+	return ret
+}
+
+// withInjection wraps a constructor so the tagged fields of the constructed instance are injected before the
+// instance is returned.
+func (c *Container) withInjection(ctor func(ctx context.Context) (interface{}, error)) func(ctx context.Context) (interface{}, error) {
+	return func(ctx context.Context) (interface{}, error) {
+		instance, err := ctor(ctx)
+		if err != nil || instance == nil {
+			return instance, err
+		}
+		if v := reflect.ValueOf(instance); v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+			return instance, nil
+		}
+		if err := c.inject(ctx, instance); err != nil {
+			return nil, err
+		}
+		return instance, nil
+	}
+}
+
+func isNotRegistered(err error) bool {
+	return errors.Is(err, ErrTypeNotRegistered) || errors.Is(err, ErrBeanNotFound) || errors.Is(err, ErrNoPrimary)
+}
@@ -0,0 +1,95 @@
+package beans_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+type injectedService struct {
+	TestServiceImpl2
+	Primary  IOther   `bean:""`
+	Named    IOther   `bean:"name2"`
+	Fallback IOther   `bean:"missing,primary"`
+	Optional INotUsed `bean:",optional"`
+	Ignored  IOther
+}
+
+type invalidInjection struct {
+	other IOther `bean:""`
+}
+
+type missingInjection struct {
+	First  INotUsed `bean:""`
+	Second IOther   `bean:"missing"`
+}
+
+func registerOthers() {
+	ShouldNotError(beans.Register((*IOther)(nil), "name1", &OtherImpl1{name: "name1"}))
+	ShouldNotError(beans.Register((*IOther)(nil), "name2", &OtherImpl1{name: "name2"}))
+	ShouldNotError(beans.SetPrimary((*IOther)(nil), "name1"))
+}
+
+func TestInject(t *testing.T) {
+	Convey("Testing Inject", t, func() {
+		Convey("Successful", t, func() {
+			before()
+			registerOthers()
+			target := &injectedService{}
+			ShouldNotError(beans.Inject(target))
+			ShouldEqual("name1", target.Primary.Name())
+			ShouldEqual("name2", target.Named.Name())
+			ShouldEqual("name1", target.Fallback.Name())
+			ShouldBeNil(target.Optional)
+			ShouldBeNil(target.Ignored)
+		})
+		Convey("All failures are aggregated", t, func() {
+			before()
+			registerOthers()
+			err := beans.Inject(&missingInjection{})
+			ShouldError(err)
+
+			var multi *beans.MultiError
+			ShouldBeTrue(errors.As(err, &multi))
+			ShouldEqual(2, len(multi.Errors))
+			ShouldBeTrue(errors.Is(err, beans.ErrTypeNotRegistered))
+			ShouldBeTrue(errors.Is(err, beans.ErrBeanNotFound))
+		})
+		Convey("Invalid targets", t, func() {
+			before()
+			ShouldError(beans.Inject(nil))
+			ShouldError(beans.Inject(injectedService{}))
+			ShouldError(beans.Inject((*injectedService)(nil)))
+			ShouldError(beans.Inject(&invalidInjection{}))
+		})
+	})
+}
+
+func TestAutoInject(t *testing.T) {
+	Convey("Testing the AutoInject option", t, func() {
+		Convey("Registered instances", t, func() {
+			before()
+			registerOthers()
+			ShouldNotError(beans.Register(ComponentType, "injected", &injectedService{}, beans.AutoInject()))
+			svc := Resolve("injected").(*injectedService)
+			ShouldEqual("name2", svc.Named.Name())
+		})
+		Convey("Constructors", t, func() {
+			before()
+			registerOthers()
+			ShouldNotError(beans.RegisterConstructor(ComponentType, "injected", func() *injectedService {
+				return &injectedService{}
+			}, beans.AutoInject()))
+			svc := Resolve("injected").(*injectedService)
+			ShouldEqual("name1", svc.Primary.Name())
+		})
+		Convey("Injection failures fail the construction", t, func() {
+			before()
+			ShouldNotError(beans.Register(ComponentType, "injected", &injectedService{}, beans.AutoInject()))
+			_, err := beans.ResolveE(ComponentType, "injected")
+			ShouldBeTrue(errors.Is(err, beans.ErrTypeNotRegistered))
+		})
+	})
+}
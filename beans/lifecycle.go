@@ -0,0 +1,143 @@
+package beans
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// createdBean is a singleton created by the container, tracked to manage its lifecycle.
+type createdBean struct {
+	dependency
+	instance interface{}
+	started  bool
+}
+
+func (c *Container) recordCreated(dep dependency, instance interface{}) {
+	c.lifeMux.Lock()
+	defer c.lifeMux.Unlock()
+	c.created = append(c.created, &createdBean{dependency: dep, instance: instance})
+}
+
+// Start starts all the created singletons that implement IStarter, in the order they were created. Singletons
+// already started are skipped. See beans.Start
+func (c *Container) Start(ctx context.Context) error {
+	c.startMux.Lock()
+	defer c.startMux.Unlock()
+
+	c.lifeMux.Lock()
+	created := append([]*createdBean{}, c.created...)
+	c.lifeMux.Unlock()
+
+	for _, bean := range created {
+		starter, ok := bean.instance.(IStarter)
+		if !ok || c.isStarted(bean) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return &BeanError{Type: bean.t, Name: bean.name, Err: err}
+		}
+		c.log().Debug(fmt.Sprintf("starting component type=%s, name=%s", bean.t.String(), bean.name))
+		if err := starter.Start(ctx); err != nil {
+			return &BeanError{Type: bean.t, Name: bean.name, Err: err}
+		}
+
+		c.lifeMux.Lock()
+		bean.started = true
+		c.lifeMux.Unlock()
+	}
+	return nil
+}
+
+// Shutdown stops all the created singletons that implement IStopper or io.Closer, in the reverse order they were
+// created. The singletons are discarded, so they are constructed again if resolved later. See beans.Shutdown
+func (c *Container) Shutdown(ctx context.Context) error {
+	c.log().Info("shutting down singleton components")
+	c.publish(ShutdownStarted{EventInfo: newEventInfo(nil, "")})
+
+	c.lifeMux.Lock()
+	created := c.created
+	c.created = nil
+	c.lifeMux.Unlock()
+	c.discardCreated(created)
+
+	var errs []error
+	for i := len(created) - 1; i >= 0; i-- {
+		bean := created[i]
+		stop := stopFunc(bean.instance)
+		if stop == nil {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, &BeanError{Type: bean.t, Name: bean.name, Err: err})
+			continue
+		}
+
+		c.log().Debug(fmt.Sprintf("stopping component type=%s, name=%s", bean.t.String(), bean.name))
+		if err := stopWithContext(ctx, stop); err != nil {
+			errs = append(errs, &BeanError{Type: bean.t, Name: bean.name, Err: err})
+		}
+	}
+
+	return newMultiError(fmt.Sprintf("unable to stop %d component(s)", len(errs)), errs)
+}
+
+// discardCreated resets the singleton instances of the provided beans, so they are constructed again when resolved.
+// Instances that were replaced since, by an override or a restored snapshot, are kept.
+func (c *Container) discardCreated(created []*createdBean) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	for _, bean := range created {
+		dep, ok := c.dependencies[bean.t]
+		if !ok {
+			continue
+		}
+		ctor, ok := dep.ctors[bean.name]
+		if !ok {
+			continue
+		}
+		if instance := ctor.instance.Load(); instance != nil && instance.instance == bean.instance {
+			ctor.instance.CompareAndSwap(instance, nil)
+		}
+	}
+}
+
+func (c *Container) isStarted(bean *createdBean) bool {
+	c.lifeMux.Lock()
+	defer c.lifeMux.Unlock()
+	return bean.started
+}
+
+// stopFunc returns the function that releases the resources of the provided instance, or nil if the instance does
+// not implement IStopper nor io.Closer
+func stopFunc(instance interface{}) func(ctx context.Context) error {
+	switch i := instance.(type) {
+	case IStopper:
+		return i.Stop
+	case io.Closer:
+		return func(context.Context) error { return i.Close() }
+	}
+	return nil
+}
+
+// stopWithContext invokes the stop function, returning the context error if the context is done before the stop
+// function returns.
+func stopWithContext(ctx context.Context, stop func(ctx context.Context) error) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("stop panicked: %v", r)
+			}
+		}()
+		done <- stop(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
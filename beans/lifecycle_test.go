@@ -0,0 +1,163 @@
+package beans_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+type lifecycleBean struct {
+	OtherImpl1
+	events  *[]string
+	initErr error
+	block   bool
+}
+
+func (b *lifecycleBean) Init() error {
+	*b.events = append(*b.events, "init "+b.name)
+	return b.initErr
+}
+
+func (b *lifecycleBean) Start(context.Context) error {
+	*b.events = append(*b.events, "start "+b.name)
+	return nil
+}
+
+func (b *lifecycleBean) Stop(ctx context.Context) error {
+	if b.block {
+		<-ctx.Done()
+		return nil
+	}
+	*b.events = append(*b.events, "stop "+b.name)
+	return nil
+}
+
+type countingStarter struct {
+	OtherImpl1
+	starts  *atomic.Int32
+	release chan struct{}
+}
+
+func (b *countingStarter) Start(context.Context) error {
+	b.starts.Add(1)
+	<-b.release
+	return nil
+}
+
+type closerBean struct {
+	OtherImpl1
+	events *[]string
+}
+
+func (b *closerBean) Close() error {
+	*b.events = append(*b.events, "close "+b.name)
+	return nil
+}
+
+func TestLifecycle(t *testing.T) {
+	Convey("Testing the lifecycle of singletons", t, func() {
+		Convey("Init, start and reverse order shutdown", t, func() {
+			c := beans.NewContainer()
+			var events []string
+			ShouldNotError(c.RegisterConstructor((*IOther)(nil), "first", func() IOther {
+				return &lifecycleBean{OtherImpl1: OtherImpl1{name: "first"}, events: &events}
+			}, beans.Singleton()))
+			ShouldNotError(c.RegisterConstructor((*IOther)(nil), "second", func() IOther {
+				return &closerBean{OtherImpl1: OtherImpl1{name: "second"}, events: &events}
+			}, beans.Singleton()))
+			ShouldNotError(c.RegisterConstructor((*IOther)(nil), "third", func(first IOther) IOther {
+				return &lifecycleBean{OtherImpl1: OtherImpl1{name: "third"}, events: &events}
+			}, beans.Singleton(), beans.ParamNames("first")))
+
+			ShouldNotError(c.InitComponents())
+			ShouldNotError(c.Start(context.Background()))
+			ShouldNotError(c.Start(context.Background()))
+			ShouldNotError(c.Shutdown(context.Background()))
+			ShouldNotError(c.Shutdown(context.Background()))
+
+			ShouldEqual([]string{
+				"init first", "init third",
+				"start first", "start third",
+				"stop third", "close second", "stop first",
+			}, events)
+		})
+		Convey("Singletons are constructed again after shutdown", t, func() {
+			c := beans.NewContainer()
+			var events []string
+			built := 0
+			ShouldNotError(c.RegisterConstructor((*IOther)(nil), "first", func() IOther {
+				built++
+				return &lifecycleBean{OtherImpl1: OtherImpl1{name: "first"}, events: &events}
+			}, beans.Singleton()))
+
+			first := c.Resolve((*IOther)(nil), "first")
+			ShouldNotError(c.Start(context.Background()))
+			ShouldNotError(c.Shutdown(context.Background()))
+
+			second := c.Resolve((*IOther)(nil), "first")
+			ShouldBeFalse(first == second)
+			ShouldEqual(2, built)
+			ShouldNotError(c.Start(context.Background()))
+			ShouldEqual([]string{"init first", "start first", "stop first", "init first", "start first"}, events)
+		})
+		Convey("Concurrent starts start each singleton once", t, func() {
+			c := beans.NewContainer()
+			var starts atomic.Int32
+			release := make(chan struct{})
+			ShouldNotError(c.Register((*IOther)(nil), "starter", &countingStarter{starts: &starts, release: release}))
+			ShouldNotError(c.InitComponents())
+
+			var wg sync.WaitGroup
+			for i := 0; i < 2; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_ = c.Start(context.Background())
+				}()
+			}
+			// The first start blocks until released, giving the second one the chance to start the bean again.
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+			wg.Wait()
+			ShouldEqual(int32(1), starts.Load())
+		})
+		Convey("Init errors fail the construction", t, func() {
+			c := beans.NewContainer()
+			var events []string
+			expected := errors.New("init failed")
+			ShouldNotError(c.Register((*IOther)(nil), "failing", &lifecycleBean{
+				OtherImpl1: OtherImpl1{name: "failing"},
+				events:     &events,
+				initErr:    expected,
+			}))
+
+			_, err := c.ResolveE((*IOther)(nil), "failing")
+			ShouldBeTrue(errors.Is(err, expected))
+			ShouldNotError(c.Shutdown(context.Background()))
+			ShouldEqual([]string{"init failing"}, events)
+		})
+		Convey("Shutdown honors the context deadline", t, func() {
+			c := beans.NewContainer()
+			var events []string
+			ShouldNotError(c.Register((*IOther)(nil), "first", &lifecycleBean{OtherImpl1: OtherImpl1{name: "first"}, events: &events}))
+			ShouldNotError(c.Register((*IOther)(nil), "blocking", &lifecycleBean{OtherImpl1: OtherImpl1{name: "blocking"}, events: &events, block: true}))
+			ShouldNotError(c.InitComponents())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err := c.Shutdown(ctx)
+
+			var multi *beans.MultiError
+			ShouldBeTrue(errors.As(err, &multi))
+			ShouldEqual(2, len(multi.Errors))
+			ShouldBeTrue(errors.Is(err, context.DeadlineExceeded))
+			ShouldEqual([]string{"init first", "init blocking"}, events)
+		})
+	})
+}
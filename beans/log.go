@@ -1,13 +1,18 @@
 package beans
 
+import "sync"
+
 var (
 	defaultLogger = &loggingHandler{}
 	logger        ILogger
+	logMux        sync.RWMutex
 )
 
 // SetLogger sets an implementation of ILogger to be used as the logger for the
 // beans package
 func SetLogger(l ILogger) {
+	logMux.Lock()
+	defer logMux.Unlock()
 	logger = l
 }
 
@@ -19,12 +24,16 @@ func LogCallbacks() ILogCallback {
 }
 
 type loggingHandler struct {
+	mux            sync.RWMutex
 	onErrHandler   ErrorCallback
 	onInfoHandler  MessageCallback
 	onDebugHandler MessageCallback
 }
 
 func log() ILogger {
+	logMux.RLock()
+	defer logMux.RUnlock()
+
 	if logger != nil {
 		return logger
 	}
@@ -32,31 +41,49 @@ func log() ILogger {
 }
 
 func (l *loggingHandler) SetErrorCallback(callback ErrorCallback) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
 	l.onErrHandler = callback
 }
 
 func (l *loggingHandler) SetInfoCallback(callback MessageCallback) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
 	l.onInfoHandler = callback
 }
 
 func (l *loggingHandler) SetDebugCallback(callback MessageCallback) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
 	l.onDebugHandler = callback
 }
 
 func (l *loggingHandler) Error(err error) {
-	if l.onErrHandler != nil {
-		l.onErrHandler(err)
+	l.mux.RLock()
+	handler := l.onErrHandler
+	l.mux.RUnlock()
+
+	if handler != nil {
+		handler(err)
 	}
 }
 
 func (l *loggingHandler) Info(msg string) {
-	if l.onInfoHandler != nil {
-		l.onInfoHandler(msg)
+	l.mux.RLock()
+	handler := l.onInfoHandler
+	l.mux.RUnlock()
+
+	if handler != nil {
+		handler(msg)
 	}
 }
 
 func (l *loggingHandler) Debug(msg string) {
-	if l.onDebugHandler != nil {
-		l.onDebugHandler(msg)
+	l.mux.RLock()
+	handler := l.onDebugHandler
+	l.mux.RUnlock()
+
+	if handler != nil {
+		handler(msg)
 	}
 }
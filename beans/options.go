@@ -0,0 +1,93 @@
+package beans
+
+// Option configures how a bean is registered into a container.
+type Option func(*beanOptions)
+
+type beanOptions struct {
+	scope      Scope
+	autoInject bool
+	paramNames []string
+	priority   *int
+	profiles   []string
+
+	conditions    []Condition
+	onMissingBean bool
+}
+
+// Singleton indicates that the bean should be treated as a singleton, which means, once the constructor is used to
+// create the instance, that instance will be always returned when requesting the bean by the given name.
+func Singleton() Option {
+	return func(o *beanOptions) {
+		o.scope = ScopeSingleton
+	}
+}
+
+// ParamNames sets the bean names to be used to resolve the parameters of a constructor, in the same order the
+// parameters are declared. An empty name, or a parameter with no name provided, resolves the primary bean of the
+// parameter type.
+//
+//   Eg.   beans.RegisterConstructor((*IService)(nil), "svc", NewService, beans.ParamNames("sql", ""))
+//
+func ParamNames(names ...string) Option {
+	return func(o *beanOptions) {
+		o.paramNames = names
+	}
+}
+
+// Scoped indicates that the bean is context scoped, which means a single instance is created for each scope (see
+// beans.NewScope) and it is shared by all the resolutions performed with the scope context (see beans.ResolveCtx).
+// The instance is disposed when the scope ends. Singletons are always constructed outside of any scope, so they cannot
+// depend on context scoped beans.
+func Scoped() Option {
+	return func(o *beanOptions) {
+		o.scope = ScopeContext
+	}
+}
+
+// AutoInject indicates that the fields of the bean instance tagged with `bean:"..."` should be injected when the bean
+// is constructed, before it is returned for the first time. See beans.Inject
+func AutoInject() Option {
+	return func(o *beanOptions) {
+		o.autoInject = true
+	}
+}
+
+// Priority sets the position of the bean when multiple beans of the same type are resolved together, such as with
+// beans.ResolveAll. Beans with a lower priority come first. It takes precedence over the IOrdered contract. It is also
+// used to order post-processors, see beans.AddPostProcessor
+func Priority(priority int) Option {
+	return func(o *beanOptions) {
+		o.priority = &priority
+	}
+}
+
+// Profiles restricts the bean to the provided profiles, so the bean is only visible when at least one of them matches
+// the active profiles (see beans.SetActiveProfiles). A profile prefixed with '!' matches when that profile is not
+// active. Beans registered with no profiles are always visible.
+//
+//   Eg.   beans.Register((*IMailer)(nil), "smtp", &SmtpMailer{}, beans.Profiles("prod"))
+//         beans.Register((*IMailer)(nil), "console", &ConsoleMailer{}, beans.Profiles("!prod"))
+//
+func Profiles(profiles ...string) Option {
+	return func(o *beanOptions) {
+		o.profiles = append(o.profiles, profiles...)
+	}
+}
+
+// apply sets the options shared by all the registration kinds into the bean.
+func (o *beanOptions) apply(info *constructorInfo) {
+	info.priority = o.priority
+	info.profiles = o.profiles
+	info.conditions = o.conditions
+	info.onMissingBean = o.onMissingBean
+}
+
+func newBeanOptions(opts ...Option) *beanOptions {
+	ret := &beanOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(ret)
+		}
+	}
+	return ret
+}
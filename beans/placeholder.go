@@ -0,0 +1,54 @@
+package beans
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// ErrUnresolvedPlaceholder indicates that a bean name contains a placeholder which property is not set and has no
+// default value, or a placeholder which is not properly closed.
+var ErrUnresolvedPlaceholder = errors.New("unresolved placeholder")
+
+// expandName replaces the '${key}' and '${key:default}' placeholders of a bean name by the values of the properties.
+func (c *Container) expandName(t reflect.Type, name string) (string, error) {
+	if !strings.Contains(name, "${") {
+		return name, nil
+	}
+
+	var sb strings.Builder
+	rest := name
+	for {
+		start := strings.Index(rest, "${")
+		if start < 0 {
+			sb.WriteString(rest)
+			return sb.String(), nil
+		}
+		end := strings.Index(rest[start:], "}")
+		if end < 0 {
+			return "", newBeanError(t, name, ErrUnresolvedPlaceholder, "the placeholder '%s' of the bean name '%s' is not closed", rest[start:], name)
+		}
+		end += start
+
+		placeholder := rest[start : end+1]
+		key, def, hasDef := strings.Cut(rest[start+2:end], ":")
+		val, ok := c.Property(key)
+		if !ok && !hasDef {
+			return "", newBeanError(t, name, ErrUnresolvedPlaceholder, "unable to resolve the placeholder '%s' of the bean name '%s', the property '%s' is not set and no default value is provided", placeholder, name, key)
+		}
+		if !ok {
+			val = def
+		}
+		sb.WriteString(rest[:start])
+		sb.WriteString(val)
+		rest = rest[end+1:]
+	}
+}
+
+// expandNameOrKeep is the same as expandName, but returns the name untouched if it cannot be expanded.
+func (c *Container) expandNameOrKeep(t reflect.Type, name string) string {
+	if expanded, err := c.expandName(t, name); err == nil {
+		return expanded
+	}
+	return name
+}
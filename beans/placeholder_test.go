@@ -0,0 +1,57 @@
+package beans_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+type alertService struct {
+	Handler IOther `bean:"${alert.type:email}"`
+}
+
+func TestPlaceholders(t *testing.T) {
+	Convey("Testing property placeholders in bean names", t, func() {
+		ref := (*IOther)(nil)
+		c := beans.NewContainer()
+		ShouldNotError(c.Register(ref, "email", &OtherImpl1{name: "email"}))
+		ShouldNotError(c.Register(ref, "sms", &OtherImpl1{name: "sms"}))
+
+		Convey("The default value is used when the property is not set", t, func() {
+			ShouldEqual("email", c.Resolve(ref, "${alert.type:email}").(IOther).Name())
+
+			svc := &alertService{}
+			ShouldNotError(c.Inject(svc))
+			ShouldEqual("email", svc.Handler.Name())
+		})
+		Convey("Placeholders are resolved against the property sources", t, func() {
+			c.SetProperty("alert.type", "sms")
+			ShouldEqual("sms", c.Resolve(ref, "${alert.type}").(IOther).Name())
+			ShouldBeTrue(c.Exists(ref, "${alert.type}"))
+
+			ShouldNotError(c.SetPrimary(ref, "${alert.type:email}"))
+			ShouldEqual("sms", c.GetPrimaryName(ref))
+
+			svc := &alertService{}
+			ShouldNotError(c.Inject(svc))
+			ShouldEqual("sms", svc.Handler.Name())
+		})
+		Convey("Placeholders are resolved in constructor parameter names", t, func() {
+			ShouldNotError(c.RegisterConstructor(ComponentType, "composed", func(other IOther) IService {
+				return &composedService{other: other}
+			}, beans.ParamNames("${alert.type}")))
+			ShouldEqual("composed-sms", c.Resolve(ComponentType, "composed").(IService).GetName())
+		})
+		Convey("A missing property with no default names the placeholder", t, func() {
+			_, err := c.ResolveE(ref, "alert-${alert.channel}")
+			ShouldBeTrue(errors.Is(err, beans.ErrUnresolvedPlaceholder))
+			ShouldEqual("unable to resolve the placeholder '${alert.channel}' of the bean name 'alert-${alert.channel}', the property 'alert.channel' is not set and no default value is provided", err.Error())
+
+			ShouldError(c.SetPrimary(ref, "${alert.channel}"))
+			_, err = c.ResolveE(ref, "${alert.type")
+			ShouldBeTrue(errors.Is(err, beans.ErrUnresolvedPlaceholder))
+		})
+	})
+}
@@ -0,0 +1,81 @@
+package beans
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+type postProcessor struct {
+	IBeanPostProcessor
+	order int
+	seq   int
+}
+
+// AddPostProcessor adds a post-processor invoked for every bean constructed by the container. See
+// beans.AddPostProcessor
+func (c *Container) AddPostProcessor(processor IBeanPostProcessor, opts ...Option) error {
+	if processor == nil {
+		return errors.New("the post-processor cannot be nil")
+	}
+	p := &postProcessor{IBeanPostProcessor: processor, order: math.MaxInt}
+	if priority := newBeanOptions(opts...).priority; priority != nil {
+		p.order = *priority
+	} else if o, ok := processor.(IOrdered); ok {
+		p.order = o.Order()
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	p.seq = len(c.processors)
+
+	// A new slice is sorted, since the current one may be in use by constructions in progress.
+	processors := append(append([]*postProcessor{}, c.processors...), p)
+	sort.SliceStable(processors, func(i, j int) bool {
+		if processors[i].order != processors[j].order {
+			return processors[i].order < processors[j].order
+		}
+		return processors[i].seq < processors[j].seq
+	})
+	c.processors = processors
+	return nil
+}
+
+// beforeInit invokes the BeforeInit hook of the post-processors for a constructed instance.
+func (c *Container) beforeInit(t reflect.Type, name string, instance interface{}) (interface{}, error) {
+	return c.postProcess(t, instance, "before", func(p *postProcessor, instance interface{}) (interface{}, error) {
+		return p.BeforeInit(t, name, instance)
+	})
+}
+
+// afterInit invokes the AfterInit hook of the post-processors for an initialized instance, and then applies the
+// decorators of the type.
+func (c *Container) afterInit(t reflect.Type, name string, instance interface{}) (interface{}, error) {
+	instance, err := c.postProcess(t, instance, "after", func(p *postProcessor, instance interface{}) (interface{}, error) {
+		return p.AfterInit(t, name, instance)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.decorate(t, instance)
+}
+
+func (c *Container) postProcess(t reflect.Type, instance interface{}, stage string, hook func(*postProcessor, interface{}) (interface{}, error)) (interface{}, error) {
+	c.mux.RLock()
+	processors := c.processors
+	c.mux.RUnlock()
+
+	for _, p := range processors {
+		processed, err := hook(p, instance)
+		if err != nil {
+			return nil, fmt.Errorf("the post-processor '%T' failed %s the initialization: %w", p.IBeanPostProcessor, stage, err)
+		}
+		if processed == nil || !reflect.TypeOf(processed).AssignableTo(t) {
+			return nil, fmt.Errorf("the post-processor '%T' returned '%T' %s the initialization, which does not implement the type '%s'", p.IBeanPostProcessor, processed, stage, t.Name())
+		}
+		instance = processed
+	}
+	return instance, nil
+}
@@ -0,0 +1,93 @@
+package beans_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+type recordingProcessor struct {
+	id    string
+	order int
+	calls *[]string
+}
+
+func (p *recordingProcessor) BeforeInit(_ reflect.Type, name string, instance interface{}) (interface{}, error) {
+	*p.calls = append(*p.calls, p.id+":before:"+name)
+	return instance, nil
+}
+
+func (p *recordingProcessor) AfterInit(_ reflect.Type, name string, instance interface{}) (interface{}, error) {
+	*p.calls = append(*p.calls, p.id+":after:"+name)
+	return instance, nil
+}
+
+func (p *recordingProcessor) Order() int {
+	return p.order
+}
+
+type replacingProcessor struct {
+	err error
+}
+
+func (p *replacingProcessor) BeforeInit(_ reflect.Type, _ string, instance interface{}) (interface{}, error) {
+	return instance, p.err
+}
+
+func (p *replacingProcessor) AfterInit(t reflect.Type, name string, instance interface{}) (interface{}, error) {
+	if t != reflect.TypeOf((*IOther)(nil)).Elem() {
+		return instance, nil
+	}
+	return &OtherImpl1{name: "replaced-" + name}, nil
+}
+
+type initializedOther struct {
+	OtherImpl1
+	calls *[]string
+}
+
+func (o *initializedOther) Init() error {
+	*o.calls = append(*o.calls, "init")
+	return nil
+}
+
+func TestPostProcessors(t *testing.T) {
+	Convey("Testing bean post-processors", t, func() {
+		ref := (*IOther)(nil)
+
+		Convey("Post-processors run around the initialization in their order", t, func() {
+			c := beans.NewContainer()
+			var calls []string
+			ShouldNotError(c.AddPostProcessor(&recordingProcessor{id: "last", order: 10, calls: &calls}))
+			ShouldNotError(c.AddPostProcessor(&recordingProcessor{id: "first", order: 10, calls: &calls}, beans.Priority(1)))
+			ShouldNotError(c.RegisterFunc(ref, "bean", func() interface{} {
+				return &initializedOther{calls: &calls}
+			}, true))
+
+			c.Resolve(ref, "bean")
+			c.Resolve(ref, "bean")
+			ShouldEqual([]string{"first:before:bean", "last:before:bean", "init", "first:after:bean", "last:after:bean"}, calls)
+		})
+		Convey("Post-processors may replace the instance before it is decorated", t, func() {
+			c := beans.NewContainer()
+			ShouldNotError(c.AddPostProcessor(&replacingProcessor{}))
+			ShouldNotError(c.Decorate(ref, prefixWith("decorated-")))
+			ShouldNotError(c.Register(ref, "bean", &OtherImpl1{name: "bean"}))
+			ShouldEqual("decorated-replaced-bean", c.Resolve(ref, "bean").(IOther).Name())
+		})
+		Convey("Errors returned by post-processors fail the construction", t, func() {
+			c := beans.NewContainer()
+			failure := errors.New("invalid bean")
+			ShouldNotError(c.AddPostProcessor(&replacingProcessor{err: failure}))
+			ShouldNotError(c.Register(ref, "bean", &OtherImpl1{name: "bean"}))
+
+			_, err := c.ResolveE(ref, "bean")
+			ShouldBeTrue(errors.Is(err, failure))
+			ShouldError(c.InitComponents())
+			ShouldError(c.AddPostProcessor(nil))
+		})
+	})
+}
@@ -0,0 +1,115 @@
+package beans
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ProfilesEnv is the environment variable used to obtain the initial active profiles of a container when it is created,
+// until they are set with SetActiveProfiles. Its value is a comma separated list of profiles, eg:
+// BEANS_PROFILES_ACTIVE=dev,local
+const ProfilesEnv = "BEANS_PROFILES_ACTIVE"
+
+// ErrProfileInactive indicates that the requested bean is registered, but none of its profiles match the active
+// profiles. It wraps ErrBeanNotFound, since an inactive bean behaves as if it was not registered.
+var ErrProfileInactive = fmt.Errorf("%w: not active for the current profiles", ErrBeanNotFound)
+
+// profileSet holds the active profiles of a container.
+type profileSet map[string]bool
+
+// SetActiveProfiles sets the active profiles of the container, which take precedence over the BEANS_PROFILES_ACTIVE
+// environment variable. See beans.SetActiveProfiles
+func (c *Container) SetActiveProfiles(profiles ...string) {
+	c.setActiveProfiles(profiles)
+}
+
+// ActiveProfiles returns the active profiles of the container. See beans.ActiveProfiles
+func (c *Container) ActiveProfiles() []string {
+	c.profileMux.RLock()
+	defer c.profileMux.RUnlock()
+	return append([]string{}, c.profiles...)
+}
+
+func (c *Container) setActiveProfiles(profiles []string) {
+	profiles = normalizeProfiles(profiles)
+	active := make(profileSet, len(profiles))
+	for _, p := range profiles {
+		active[p] = true
+	}
+
+	c.profileMux.Lock()
+	defer c.profileMux.Unlock()
+	c.profiles = profiles
+	c.active = active
+}
+
+// activeProfiles returns the set of the active profiles. The set is replaced, never modified, when the profiles change,
+// so it must not be modified by the caller.
+func (c *Container) activeProfiles() profileSet {
+	c.profileMux.RLock()
+	defer c.profileMux.RUnlock()
+	return c.active
+}
+
+func profilesFromEnv() []string {
+	return strings.Split(os.Getenv(ProfilesEnv), ",")
+}
+
+// matches indicates if a bean registered for the provided profile expressions is active. A bean with no profiles is
+// always active, otherwise at least one of the expressions must match.
+func (s profileSet) matches(profiles []string) bool {
+	if len(profiles) == 0 {
+		return true
+	}
+	for _, p := range profiles {
+		if strings.HasPrefix(p, "!") {
+			if !s[p[1:]] {
+				return true
+			}
+		} else if s[p] {
+			return true
+		}
+	}
+	return false
+}
+
+func (s profileSet) String() string {
+	ret := make([]string, 0, len(s))
+	for p := range s {
+		ret = append(ret, p)
+	}
+	sort.Strings(ret)
+	return "[" + strings.Join(ret, ", ") + "]"
+}
+
+// isActive indicates if the bean is active for the provided profiles.
+func (c *constructorInfo) isActive(active profileSet) bool {
+	return active.matches(c.profiles)
+}
+
+func errProfileInactive(dep dependency, profiles []string, active profileSet) error {
+	return newBeanError(dep.t, dep.name, ErrProfileInactive,
+		"dependency %s is not active, it is registered for the profiles [%s] and the active profiles are %s",
+		dep.name, strings.Join(profiles, ", "), active.String())
+}
+
+func validateProfiles(profiles []string) error {
+	for _, p := range profiles {
+		if name := strings.TrimPrefix(p, "!"); name == "" || strings.TrimSpace(name) != name || strings.ContainsAny(name, ",!") {
+			return fmt.Errorf("invalid profile expression '%s'", p)
+		}
+	}
+	return nil
+}
+
+func normalizeProfiles(profiles []string) []string {
+	ret := make([]string, 0, len(profiles))
+	for _, p := range profiles {
+		if p = strings.TrimSpace(p); p != "" {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,86 @@
+package beans_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+func TestProfiles(t *testing.T) {
+	Convey("Testing beans registered under profiles", t, func() {
+		c := beans.NewContainer()
+		ref := (*IOther)(nil)
+		ShouldNotError(c.Register(ref, "smtp", &OtherImpl1{name: "smtp"}, beans.Profiles("prod")))
+		ShouldNotError(c.Register(ref, "console", &OtherImpl1{name: "console"}, beans.Profiles("!prod")))
+		ShouldNotError(c.Register(ref, "audit", &OtherImpl1{name: "audit"}, beans.Profiles("dev", "test")))
+
+		Convey("Only the beans matching the active profiles are visible", t, func() {
+			c.SetActiveProfiles("prod")
+			ShouldEqual("smtp", c.Primary(ref).(IOther).Name())
+			ShouldBeTrue(c.Exists(ref, "smtp"))
+			ShouldBeFalse(c.Exists(ref, "console"))
+			ShouldBeFalse(c.Exists(ref, "audit"))
+
+			all, err := c.ResolveMap(ref)
+			ShouldNotError(err)
+			ShouldEqual(1, len(all))
+		})
+		Convey("A bean is active when any of its profiles matches", t, func() {
+			c.SetActiveProfiles("test")
+			ShouldEqual("audit", c.Resolve(ref, "audit").(IOther).Name())
+			ShouldEqual("console", c.Resolve(ref, "console").(IOther).Name())
+
+			_, err := c.PrimaryE(ref)
+			ShouldBeTrue(errors.Is(err, beans.ErrAmbiguousPrimary))
+		})
+		Convey("Errors explain why a bean was excluded", t, func() {
+			c.SetActiveProfiles("dev")
+			_, err := c.ResolveE(ref, "smtp")
+			ShouldBeTrue(errors.Is(err, beans.ErrProfileInactive))
+			ShouldBeTrue(errors.Is(err, beans.ErrBeanNotFound))
+			ShouldEqual("dependency smtp is not active, it is registered for the profiles [prod] and the active profiles are [dev]", err.Error())
+		})
+		Convey("The primary is reported as missing when all beans are excluded", t, func() {
+			c2 := beans.NewContainer()
+			c2.SetActiveProfiles()
+			ShouldNotError(c2.Register(ref, "smtp", &OtherImpl1{name: "smtp"}, beans.Profiles("prod")))
+			_, err := c2.PrimaryE(ref)
+			ShouldBeTrue(errors.Is(err, beans.ErrNoPrimary))
+			ShouldEqual("no primary dependency found for type 'IOther', 1 dependencies are not active for the active profiles []", err.Error())
+		})
+		Convey("Invalid profile expressions are rejected", t, func() {
+			ShouldError(c.Register(ref, "invalid", &OtherImpl1{}, beans.Profiles("!")))
+			ShouldError(c.Register(ref, "invalid", &OtherImpl1{}, beans.Profiles("")))
+		})
+	})
+}
+
+func TestProfilesFromEnvironment(t *testing.T) {
+	Convey("Testing the active profiles are read from the environment", t, func() {
+		t.Setenv(beans.ProfilesEnv, "dev, local")
+		c := beans.NewContainer()
+		ShouldEqual([]string{"dev", "local"}, c.ActiveProfiles())
+
+		// The environment is only read when the container is created.
+		t.Setenv(beans.ProfilesEnv, "prod")
+		ShouldEqual([]string{"dev", "local"}, c.ActiveProfiles())
+
+		built := 0
+		ShouldNotError(c.RegisterConstructor((*IOther)(nil), "local", func() IOther {
+			built++
+			return &OtherImpl1{name: "local"}
+		}, beans.Singleton(), beans.Profiles("local")))
+		ShouldNotError(c.RegisterConstructor((*IOther)(nil), "remote", func() IOther {
+			built++
+			return &OtherImpl1{name: "remote"}
+		}, beans.Singleton(), beans.Profiles("prod")))
+		ShouldNotError(c.InitComponents())
+		ShouldEqual(1, built)
+
+		c.SetActiveProfiles("prod")
+		ShouldEqual([]string{"prod"}, c.ActiveProfiles())
+		ShouldEqual("remote", c.Primary((*IOther)(nil)).(IOther).Name())
+	})
+}
@@ -0,0 +1,274 @@
+package beans
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Property source precedences. Properties set with SetProperty take precedence over every source, sources with a lower
+// precedence value take precedence over sources with a higher value, and among sources with the same precedence the
+// source added last takes precedence.
+const (
+	precedenceFlags = iota
+	precedenceEnv
+	precedenceFile
+)
+
+// PropertySource provides the values of the properties used by the container, such as environment variables, JSON or
+// YAML files and command line flags. See beans.AddPropertySource
+type PropertySource struct {
+	name       string
+	precedence int
+	lookup     func(key string) (string, bool)
+	keys       func() []string
+}
+
+// Name returns the name of the property source.
+func (s *PropertySource) Name() string {
+	return s.name
+}
+
+// Lookup returns the value of the property by the given key.
+func (s *PropertySource) Lookup(key string) (string, bool) {
+	return s.lookup(key)
+}
+
+// envSource is the property source of the environment variables, which every container includes.
+var envSource = &PropertySource{
+	name:       "environment",
+	precedence: precedenceEnv,
+	lookup: func(key string) (string, bool) {
+		if val, ok := os.LookupEnv(envKey(key)); ok || !strings.Contains(key, "_") {
+			return val, ok
+		}
+		// Keys with underscores are also read from the unescaped name, eg: 'db.max_idle' -> 'DB_MAX_IDLE'
+		return os.LookupEnv(strings.ReplaceAll(envKey(key), "__", "_"))
+	},
+	keys: func() []string {
+		var ret []string
+		for _, kv := range os.Environ() {
+			if i := strings.Index(kv, "="); i > 0 {
+				ret = append(ret, propertyKey(kv[:i]))
+			}
+		}
+		return ret
+	},
+}
+
+// FlagProperties creates a property source from the flags of the provided flag set, where the flag names are used as
+// the property keys. Only the flags set in the command line are used, the default values of the flags are ignored. If
+// the flag set is nil, flag.CommandLine is used.
+//
+// The flags are read every time a property is requested, so the source may be added before the flags are parsed.
+func FlagProperties(fs *flag.FlagSet) *PropertySource {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	set := func() map[string]string {
+		ret := map[string]string{}
+		fs.Visit(func(f *flag.Flag) {
+			ret[f.Name] = f.Value.String()
+		})
+		return ret
+	}
+	return &PropertySource{
+		name:       "flags",
+		precedence: precedenceFlags,
+		lookup: func(key string) (string, bool) {
+			val, ok := set()[key]
+			return val, ok
+		},
+		keys: func() []string {
+			return mapKeys(set())
+		},
+	}
+}
+
+// MapProperties creates a property source from a map. Nested maps and lists are flattened, so the key 'host' of the
+// nested map 'smtp' is available as 'smtp.host', and the elements of a list 'servers' as 'servers.0', 'servers.1', etc.
+func MapProperties(name string, values map[string]interface{}) *PropertySource {
+	flat := map[string]string{}
+	flatten("", values, flat)
+	return &PropertySource{
+		name:       name,
+		precedence: precedenceFile,
+		lookup: func(key string) (string, bool) {
+			val, ok := flat[key]
+			return val, ok
+		},
+		keys: func() []string {
+			return mapKeys(flat)
+		},
+	}
+}
+
+// JSONProperties creates a property source from JSON data. See MapProperties
+func JSONProperties(name string, data []byte) (*PropertySource, error) {
+	values := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("unable to parse the JSON properties '%s': %w", name, err)
+	}
+	return MapProperties(name, values), nil
+}
+
+// YAMLProperties creates a property source from YAML data. See MapProperties
+func YAMLProperties(name string, data []byte) (*PropertySource, error) {
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("unable to parse the YAML properties '%s': %w", name, err)
+	}
+	return MapProperties(name, values), nil
+}
+
+// JSONFile creates a property source from a JSON file. See MapProperties
+func JSONFile(path string) (*PropertySource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return JSONProperties(path, data)
+}
+
+// YAMLFile creates a property source from a YAML file. See MapProperties
+func YAMLFile(path string) (*PropertySource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return YAMLProperties(path, data)
+}
+
+// AddPropertySource adds a property source to the container. See beans.AddPropertySource
+func (c *Container) AddPropertySource(source *PropertySource) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.sources = append(c.sources, source)
+}
+
+// SetProperty sets the value of a property of the container. See beans.SetProperty
+func (c *Container) SetProperty(key, value string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.properties == nil {
+		c.properties = map[string]string{}
+	}
+	c.properties[key] = value
+}
+
+// Property returns the value of a property of the container. See beans.Property
+func (c *Container) Property(key string) (string, bool) {
+	c.mux.RLock()
+	val, ok := c.properties[key]
+	c.mux.RUnlock()
+	if ok {
+		return val, true
+	}
+	for _, source := range c.propertySources() {
+		if val, ok := source.Lookup(key); ok {
+			return val, true
+		}
+	}
+	return "", false
+}
+
+// propertyKeys returns the distinct keys of all the properties under the provided prefix, with the prefix removed.
+func (c *Container) propertyKeys(prefix string) []string {
+	prefix += "."
+	set := map[string]string{}
+	add := func(key string) {
+		if strings.HasPrefix(key, prefix) && len(key) > len(prefix) {
+			set[key[len(prefix):]] = ""
+		}
+	}
+
+	c.mux.RLock()
+	for key := range c.properties {
+		add(key)
+	}
+	c.mux.RUnlock()
+	for _, source := range c.propertySources() {
+		for _, key := range source.keys() {
+			add(key)
+		}
+	}
+	return mapKeys(set)
+}
+
+// propertySources returns the property sources sorted by their precedence.
+func (c *Container) propertySources() []*PropertySource {
+	c.mux.RLock()
+	ret := append([]*PropertySource{envSource}, c.sources...)
+	c.mux.RUnlock()
+
+	// Reversed first, so the sources added last take precedence among the sources with the same precedence.
+	for i, j := 0, len(ret)-1; i < j; i, j = i+1, j-1 {
+		ret[i], ret[j] = ret[j], ret[i]
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].precedence < ret[j].precedence
+	})
+	return ret
+}
+
+// envKey returns the name of the environment variable for a property key, eg: 'smtp.host' -> 'SMTP_HOST'. Underscores
+// in the key are escaped as double underscores, eg: 'db.max_idle' -> 'DB_MAX__IDLE'
+func envKey(key string) string {
+	return strings.ToUpper(strings.NewReplacer("_", "__", ".", "_", "-", "_").Replace(key))
+}
+
+// propertyKey returns the property key for the name of an environment variable, the reverse of envKey, eg:
+// 'DB_MAX__IDLE' -> 'db.max_idle'
+func propertyKey(env string) string {
+	parts := strings.Split(strings.ToLower(env), "__")
+	for i, part := range parts {
+		parts[i] = strings.ReplaceAll(part, "_", ".")
+	}
+	return strings.Join(parts, "_")
+}
+
+func flatten(prefix string, val interface{}, out map[string]string) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			flatten(join(key), item, out)
+		}
+	case map[interface{}]interface{}:
+		for key, item := range v {
+			flatten(join(fmt.Sprint(key)), item, out)
+		}
+	case []interface{}:
+		for i, item := range v {
+			flatten(join(strconv.Itoa(i)), item, out)
+		}
+	case nil:
+		out[prefix] = ""
+	default:
+		out[prefix] = fmt.Sprint(v)
+	}
+}
+
+func mapKeys(m map[string]string) []string {
+	ret := make([]string, 0, len(m))
+	for key := range m {
+		ret = append(ret, key)
+	}
+	sort.Strings(ret)
+	return ret
+}
@@ -0,0 +1,194 @@
+package beans_test
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+type smtpConfig struct {
+	Host    string            `config:"host" default:"localhost"`
+	Port    int               `config:"port" default:"25"`
+	Timeout time.Duration     `config:"timeout" default:"10s"`
+	To      []string          `config:"to"`
+	Headers map[string]string `config:"headers"`
+	Retry   struct {
+		Attempts uint8   `config:"attempts" default:"3"`
+		Backoff  float64 `config:"backoff"`
+	} `config:"retry"`
+	Ignored string
+}
+
+const yamlProperties = `
+smtp:
+  host: smtp.yaml.com
+  port: 2525
+  to:
+    - ops@example.com
+    - dev@example.com
+  headers:
+    x-team: platform
+  retry:
+    backoff: 1.5
+`
+
+const jsonProperties = `{"smtp": {"host": "smtp.json.com", "timeout": "1m"}}`
+
+func TestPropertySources(t *testing.T) {
+	Convey("Testing the precedence of the property sources", t, func() {
+		c := beans.NewContainer()
+		yamlSrc, err := beans.YAMLProperties("application.yaml", []byte(yamlProperties))
+		ShouldNotError(err)
+		jsonSrc, err := beans.JSONProperties("application.json", []byte(jsonProperties))
+		ShouldNotError(err)
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("smtp.port", "1", "")
+
+		c.AddPropertySource(beans.FlagProperties(fs))
+		c.AddPropertySource(yamlSrc)
+		c.AddPropertySource(jsonSrc)
+
+		Convey("Files added later take precedence over files added first", t, func() {
+			val, ok := c.Property("smtp.host")
+			ShouldBeTrue(ok)
+			ShouldEqual("smtp.json.com", val)
+			val, _ = c.Property("smtp.to.1")
+			ShouldEqual("dev@example.com", val)
+		})
+		Convey("Environment variables take precedence over files", t, func() {
+			t.Setenv("SMTP_HOST", "smtp.env.com")
+			val, _ := c.Property("smtp.host")
+			ShouldEqual("smtp.env.com", val)
+		})
+		Convey("Only the flags set take precedence over the environment", t, func() {
+			t.Setenv("SMTP_PORT", "587")
+			val, _ := c.Property("smtp.port")
+			ShouldEqual("587", val)
+			ShouldNotError(fs.Parse([]string{"-smtp.port=465"}))
+			val, _ = c.Property("smtp.port")
+			ShouldEqual("465", val)
+		})
+		Convey("Properties set in code take precedence over every source", t, func() {
+			c.SetProperty("smtp.port", "26")
+			val, _ := c.Property("smtp.port")
+			ShouldEqual("26", val)
+		})
+		Convey("Invalid files are reported", t, func() {
+			_, err := beans.JSONProperties("invalid.json", []byte("{"))
+			ShouldError(err)
+			_, err = beans.YAMLFile(filepath.Join(t.TempDir(), "missing.yaml"))
+			ShouldError(err)
+		})
+	})
+}
+
+func TestBind(t *testing.T) {
+	Convey("Testing config structs binding", t, func() {
+		Convey("Properties are converted to the field types", t, func() {
+			c := beans.NewContainer()
+			src, err := beans.YAMLProperties("application.yaml", []byte(yamlProperties))
+			ShouldNotError(err)
+			c.AddPropertySource(src)
+
+			cfg := &smtpConfig{Ignored: "untouched"}
+			ShouldNotError(c.Bind("smtp", cfg))
+			ShouldEqual("smtp.yaml.com", cfg.Host)
+			ShouldEqual(2525, cfg.Port)
+			ShouldEqual(10*time.Second, cfg.Timeout)
+			ShouldEqual([]string{"ops@example.com", "dev@example.com"}, cfg.To)
+			ShouldEqual(map[string]string{"x-team": "platform"}, cfg.Headers)
+			ShouldEqual(uint8(3), cfg.Retry.Attempts)
+			ShouldEqual(1.5, cfg.Retry.Backoff)
+			ShouldEqual("untouched", cfg.Ignored)
+		})
+		Convey("Lists and maps are read from comma separated values", t, func() {
+			c := beans.NewContainer()
+			c.SetProperty("mail.to", "a@example.com, b@example.com")
+			c.SetProperty("mail.headers", "a=1,b=2")
+			c.SetProperty("mail.timeout", "500ms")
+
+			cfg := &smtpConfig{}
+			ShouldNotError(c.Bind("mail", cfg))
+			ShouldEqual([]string{"a@example.com", "b@example.com"}, cfg.To)
+			ShouldEqual(map[string]string{"a": "1", "b": "2"}, cfg.Headers)
+			ShouldEqual(500*time.Millisecond, cfg.Timeout)
+			ShouldEqual("localhost", cfg.Host)
+		})
+		Convey("List indexes must be contiguous", t, func() {
+			c := beans.NewContainer()
+			c.SetProperty("mail.to.0", "a@example.com")
+			c.SetProperty("mail.to.2000000000", "b@example.com")
+
+			err := c.Bind("mail", &smtpConfig{})
+			ShouldError(err)
+			ShouldContain(err.Error(), "the index 2000000000 is out of range")
+
+			for _, index := range []string{"00", "+1"} {
+				c = beans.NewContainer()
+				c.SetProperty("mail.to.0", "a@example.com")
+				c.SetProperty("mail.to."+index, "b@example.com")
+				err = c.Bind("mail", &smtpConfig{})
+				ShouldError(err)
+				ShouldContain(err.Error(), "invalid index '"+index+"'")
+			}
+
+			c = beans.NewContainer()
+			c.SetProperty("mail.to.1", "b@example.com")
+			c.SetProperty("mail.to.0", "a@example.com")
+			cfg := &smtpConfig{}
+			ShouldNotError(c.Bind("mail", cfg))
+			ShouldEqual([]string{"a@example.com", "b@example.com"}, cfg.To)
+		})
+		Convey("Underscores in the keys are escaped in the environment", t, func() {
+			t.Setenv("POOL_LIMITS__BY__HOST_A", "1")
+			t.Setenv("POOL_MAX__IDLE", "5")
+			c := beans.NewContainer()
+
+			cfg := &struct {
+				MaxIdle int            `config:"max_idle"`
+				Limits  map[string]int `config:"limits_by_host"`
+			}{}
+			ShouldNotError(c.Bind("pool", cfg))
+			ShouldEqual(5, cfg.MaxIdle)
+			ShouldEqual(map[string]int{"a": 1}, cfg.Limits)
+		})
+		Convey("Conversion failures are aggregated", t, func() {
+			c := beans.NewContainer()
+			c.SetProperty("smtp.port", "abc")
+			c.SetProperty("smtp.timeout", "forever")
+
+			err := c.Bind("smtp", &smtpConfig{})
+			var multi *beans.MultiError
+			ShouldBeTrue(errors.As(err, &multi))
+			ShouldEqual(2, len(multi.Errors))
+			ShouldError(c.Bind("smtp", smtpConfig{}))
+		})
+	})
+}
+
+func TestRegisterConfig(t *testing.T) {
+	Convey("Testing config structs registered as beans", t, func() {
+		c := beans.NewContainer()
+		path := filepath.Join(t.TempDir(), "application.json")
+		ShouldNotError(os.WriteFile(path, []byte(jsonProperties), 0o600))
+
+		ShouldNotError(c.RegisterConfig("smtp", "smtp", &smtpConfig{}))
+		ShouldNotError(c.RegisterConstructor((*IOther)(nil), "mailer", func(cfg *smtpConfig) IOther {
+			return &OtherImpl1{name: cfg.Host}
+		}))
+
+		src, err := beans.JSONFile(path)
+		ShouldNotError(err)
+		c.AddPropertySource(src)
+
+		ShouldEqual("smtp.json.com", c.Resolve((*IOther)(nil), "mailer").(IOther).Name())
+		cfg := c.Resolve((**smtpConfig)(nil), "smtp").(*smtpConfig)
+		ShouldEqual(time.Minute, cfg.Timeout)
+	})
+}
@@ -0,0 +1,156 @@
+package beans
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// ErrHandleNotBound indicates that a Provider or a Lazy handle was used without being injected or created with
+// NewProvider or NewLazy
+var ErrHandleNotBound = errors.New("the handle is not bound to a container, it must be injected or created with NewProvider or NewLazy")
+
+var handleType = reflect.TypeOf((*handle)(nil)).Elem()
+
+// handle is implemented by the pointers to the injection handles, which are bound to the bean they resolve when they
+// are injected instead of the bean instance.
+type handle interface {
+	bind(c *Container, ctx context.Context, name string)
+}
+
+// Provider is a handle to a bean which is resolved every time Get is invoked, rather than when the bean that depends
+// on it is constructed. Constructor parameters and injected fields of the type Provider[T] are injected with a handle
+// to the bean of type T by the given name, or to the primary bean of type T if no name is given.
+//
+// Resolving the bean on use breaks the dependency cycles between beans constructed at the same time, and defers the
+// construction of expensive beans until they are needed. Prototype beans are constructed again every time Get is
+// invoked, while singletons are constructed once.
+//
+//	func NewNotifier(handlers beans.Provider[IAlertHandler]) *Notifier
+//
+//	type Notifier struct {
+//	    Handler beans.Provider[IAlertHandler] `bean:"email"`
+//	}
+type Provider[T any] struct {
+	c    *Container
+	ctx  context.Context
+	name string
+}
+
+// NewProvider returns a handle to the bean of type T by the given name, resolved from the provided container every
+// time Get is invoked. An empty name refers to the primary bean of type T, and a nil container to the default
+// container.
+func NewProvider[T any](c *Container, name string) Provider[T] {
+	if c == nil {
+		c = defaultContainer
+	}
+	return Provider[T]{c: c, ctx: context.Background(), name: name}
+}
+
+// Get resolves the bean. Failures are reported to the logger of the container and the zero value of T is returned,
+// use GetE to obtain the error instead.
+func (p Provider[T]) Get() T {
+	ret, err := p.GetE()
+	if err != nil {
+		c := p.c
+		if c == nil {
+			c = defaultContainer
+		}
+		c.log().Error(err)
+	}
+	return ret
+}
+
+// GetE resolves the bean, returning an error if the bean cannot be resolved.
+func (p Provider[T]) GetE() (T, error) {
+	var zero T
+	if p.c == nil {
+		return zero, ErrHandleNotBound
+	}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	val, err := p.c.getE(p.ctx, t, p.name)
+	if err != nil || val == nil {
+		return zero, err
+	}
+	ret, ok := val.(T)
+	if !ok {
+		return zero, fmt.Errorf("unable to resolve %s: the resolved bean of type '%T' is not assignable", beanKey(t, p.name), val)
+	}
+	return ret, nil
+}
+
+func (p *Provider[T]) bind(c *Container, ctx context.Context, name string) {
+	*p = Provider[T]{c: c, ctx: ctx, name: name}
+}
+
+// Lazy is a handle to a bean which is resolved the first time Get is invoked, and reused afterwards. Constructor
+// parameters and injected fields of the type Lazy[T] are injected with a handle to the bean of type T by the given
+// name, or to the primary bean of type T if no name is given. See Provider
+//
+// Unlike Provider, a Lazy handle to a prototype bean constructs a single instance. Copies of the handle share the
+// resolved instance.
+type Lazy[T any] struct {
+	state *lazyState[T]
+}
+
+type lazyState[T any] struct {
+	provider Provider[T]
+	instance atomic.Pointer[T]
+}
+
+// NewLazy returns a handle to the bean of type T by the given name, resolved from the provided container the first
+// time Get is invoked. An empty name refers to the primary bean of type T, and a nil container to the default
+// container.
+func NewLazy[T any](c *Container, name string) Lazy[T] {
+	return Lazy[T]{state: &lazyState[T]{provider: NewProvider[T](c, name)}}
+}
+
+// Get resolves the bean the first time it is invoked and returns the same instance afterwards. Failures are reported
+// to the logger of the container and the zero value of T is returned, use GetE to obtain the error instead.
+func (l Lazy[T]) Get() T {
+	if l.state == nil {
+		return Provider[T]{}.Get()
+	}
+	ret, err := l.GetE()
+	if err != nil {
+		l.state.provider.c.log().Error(err)
+	}
+	return ret
+}
+
+// GetE resolves the bean the first time it is invoked and returns the same instance afterwards. A failed resolution is
+// attempted again the next time the bean is requested.
+func (l Lazy[T]) GetE() (T, error) {
+	if l.state == nil {
+		var zero T
+		return zero, ErrHandleNotBound
+	}
+	if instance := l.state.instance.Load(); instance != nil {
+		return *instance, nil
+	}
+	instance, err := l.state.provider.GetE()
+	if err != nil {
+		return instance, err
+	}
+	// If the bean was resolved concurrently, the first instance stored is kept.
+	l.state.instance.CompareAndSwap(nil, &instance)
+	return *l.state.instance.Load(), nil
+}
+
+func (l *Lazy[T]) bind(c *Container, ctx context.Context, name string) {
+	l.state = &lazyState[T]{provider: Provider[T]{c: c, ctx: ctx, name: name}}
+}
+
+// isHandle indicates whether the type is an injection handle, such as Provider or Lazy
+func isHandle(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && reflect.PtrTo(t).Implements(handleType)
+}
+
+// newHandle returns an injection handle of the type 't' bound to the bean by the given name.
+func (c *Container) newHandle(ctx context.Context, t reflect.Type, name string) reflect.Value {
+	v := reflect.New(t)
+	v.Interface().(handle).bind(c, ctx, name)
+	return v.Elem()
+}
@@ -0,0 +1,106 @@
+package beans_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+type providedService struct {
+	TestServiceImpl2
+	other beans.Provider[IOther]
+}
+
+func (s *providedService) GetName() string {
+	return "provided-" + s.other.Get().Name()
+}
+
+type lazyService struct {
+	TestServiceImpl2
+	other beans.Lazy[IOther]
+}
+
+func (s *lazyService) GetName() string {
+	return "lazy-" + s.other.Get().Name()
+}
+
+type lazyInjection struct {
+	Provider beans.Provider[IOther] `bean:"name2"`
+	Lazy     beans.Lazy[IOther]     `bean:""`
+	Missing  beans.Lazy[INotUsed]   `bean:""`
+}
+
+func TestProvider(t *testing.T) {
+	Convey("Testing Provider and Lazy handles", t, func() {
+		Convey("Constructor cycles are broken by a provider", t, func() {
+			before()
+			ShouldNotError(beans.RegisterConstructor(ComponentType, "bean1", func(other beans.Provider[IOther]) IService {
+				return &providedService{other: other}
+			}, beans.Singleton()))
+			ShouldNotError(beans.RegisterConstructor((*IOther)(nil), "sql", func(svc IService) IOther {
+				return &OtherImpl1{name: "sql-" + svc.(*providedService).TestServiceImpl2.GetName()}
+			}, beans.ParamNames("bean1")))
+
+			ShouldEqual("provided-sql-bean2", Resolve("bean1").GetName())
+		})
+		Convey("A provider constructs a new prototype on every call", t, func() {
+			before()
+			count := 0
+			ShouldNotError(beans.RegisterConstructor((*IOther)(nil), "proto", func() IOther {
+				count++
+				return &OtherImpl1{name: "proto"}
+			}))
+			provider := beans.NewProvider[IOther](nil, "proto")
+			ShouldBeFalse(provider.Get() == provider.Get())
+			ShouldEqual(2, count)
+
+			lazy := beans.NewLazy[IOther](nil, "proto")
+			ShouldBeTrue(lazy.Get() == lazy.Get())
+			ShouldEqual(3, count)
+		})
+		Convey("A provider returns the same singleton on every call", t, func() {
+			before()
+			ShouldNotError(beans.RegisterConstructor((*IOther)(nil), "single", func() IOther {
+				return &OtherImpl1{name: "single"}
+			}, beans.Singleton()))
+			provider := beans.NewProvider[IOther](nil, "single")
+			ShouldBeTrue(provider.Get() == provider.Get())
+		})
+		Convey("A lazy dependency is not constructed until it is used", t, func() {
+			before()
+			constructed := false
+			ShouldNotError(beans.RegisterConstructor((*IOther)(nil), "expensive", func() IOther {
+				constructed = true
+				return &OtherImpl1{name: "expensive"}
+			}, beans.Singleton()))
+			ShouldNotError(beans.RegisterConstructor(ComponentType, "bean1", func(other beans.Lazy[IOther]) IService {
+				return &lazyService{other: other}
+			}, beans.ParamNames("expensive")))
+
+			svc := Resolve("bean1")
+			ShouldBeFalse(constructed)
+			ShouldEqual("lazy-expensive", svc.GetName())
+			ShouldBeTrue(constructed)
+		})
+		Convey("Fields are injected with handles", t, func() {
+			before()
+			registerOthers()
+			target := &lazyInjection{}
+			ShouldNotError(beans.Inject(target))
+			ShouldEqual("name2", target.Provider.Get().Name())
+			ShouldEqual("name1", target.Lazy.Get().Name())
+
+			_, err := target.Missing.GetE()
+			ShouldBeTrue(errors.Is(err, beans.ErrTypeNotRegistered))
+		})
+		Convey("Handles that are not bound fail to resolve", t, func() {
+			before()
+			_, err := beans.Provider[IOther]{}.GetE()
+			ShouldBeTrue(errors.Is(err, beans.ErrHandleNotBound))
+			_, err = beans.Lazy[IOther]{}.GetE()
+			ShouldBeTrue(errors.Is(err, beans.ErrHandleNotBound))
+		})
+	})
+}
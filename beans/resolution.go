@@ -0,0 +1,298 @@
+package beans
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrCircularDependency indicates that a bean depends, directly or indirectly, on itself. The cycles are detected
+// through the context passed to the constructors, so constructors that resolve beans manually should use the context
+// they receive, eg: beans.ResolveCtxE(ctx, (*IService)(nil), "name")
+var ErrCircularDependency = errors.New("circular dependency")
+
+type frameCtxKey struct{}
+
+// resolution identifies a chain of nested constructions, started by a resolution that is not nested in the
+// construction of another bean. The fields are guarded by the 'buildMux' of the container.
+type resolution struct {
+	// gid is the id of the goroutine that started the resolution. It is only obtained once the resolution holds the
+	// lock of a singleton, see acquire.
+	gid uint64
+	// current is the innermost construction of the resolution.
+	current *resolutionFrame
+	// waiting is the frame of the construction waiting for the lock 'waitingOn', held by another resolution.
+	waiting   *resolutionFrame
+	waitingOn *constructionLock
+}
+
+// resolutionFrame is the construction of a bean within a resolution chain. Frames are carried by the context passed
+// to the constructors, so the nested resolutions triggered by a constructor are tracked as long as it resolves its
+// dependencies with that context.
+type resolutionFrame struct {
+	res    *resolution
+	dep    dependency
+	parent *resolutionFrame
+	// done is set once the construction finishes, so contexts retained by the constructed beans do not extend the
+	// chain. Guarded by the 'buildMux' of the container.
+	done bool
+}
+
+// constructionLock serializes the construction of a bean instance, recording the frame that holds it so resolutions
+// waiting on each other can be detected instead of blocking forever.
+type constructionLock struct {
+	mux sync.Mutex
+	// owner is guarded by the 'buildMux' of the container.
+	owner *resolutionFrame
+}
+
+func frameFrom(ctx context.Context) *resolutionFrame {
+	if ctx == nil {
+		return nil
+	}
+	f, _ := ctx.Value(frameCtxKey{}).(*resolutionFrame)
+	return f
+}
+
+func withFrame(ctx context.Context, f *resolutionFrame) context.Context {
+	return context.WithValue(ctx, frameCtxKey{}, f)
+}
+
+// enterConstruction registers the provided bean as being constructed by the resolution chain carried by the context,
+// and returns the context to construct the bean with. If the bean is already being constructed in the chain, a
+// circular dependency exists and an error describing the full chain is returned.
+//
+// A constructor that does not pass along its context, such as the functions registered with RegisterFunc, starts a new
+// chain for each bean it resolves. Cycles through such constructors are only detected when they reach a singleton
+// being constructed, see acquire.
+//
+// The returned function must be invoked once the construction finishes.
+func (c *Container) enterConstruction(ctx context.Context, dep dependency) (context.Context, func(), error) {
+	c.buildMux.Lock()
+	defer c.buildMux.Unlock()
+
+	parent := frameFrom(ctx)
+	if parent != nil && parent.done {
+		parent = nil
+	}
+	for f := parent; f != nil; f = f.parent {
+		if f.dep == dep {
+			keys := append(chainKeys(f, parent), beanKey(dep.t, dep.name))
+			return nil, nil, newBeanError(dep.t, dep.name, ErrCircularDependency, "circular dependency detected: %s", strings.Join(keys, " -> "))
+		}
+	}
+
+	frame := &resolutionFrame{dep: dep, parent: parent}
+	if parent != nil {
+		frame.res = parent.res
+		c.discover(parent.dep, dep)
+	} else {
+		frame.res = &resolution{}
+	}
+	frame.res.current = frame
+
+	return withFrame(ctx, frame), func() {
+		c.buildMux.Lock()
+		defer c.buildMux.Unlock()
+
+		frame.done = true
+		if frame.res.current == frame {
+			frame.res.current = frame.parent
+		}
+	}, nil
+}
+
+// acquire locks the construction lock for the construction carried by the context. If the lock is held by another
+// resolution that, directly or through other resolutions, waits for a lock held by this resolution, waiting would
+// never finish and an error describing the cycle is returned instead.
+//
+// The same applies if the lock is held by a resolution started by the current goroutine, since the lock is not
+// re-entrant. That happens when a constructor resolves the bean being constructed without the context it received,
+// which only singletons are able to reach, so the goroutines are only tracked for the locks acquired with 'singleton'
+// set. The context cannot tell that case apart from a lock held by another goroutine, see goroutineID. Since obtaining
+// the id of a goroutine is expensive, it is obtained once per resolution holding such a lock, and by the resolutions
+// finding it held by another resolution.
+//
+// The returned function releases the lock.
+func (c *Container) acquire(ctx context.Context, l *constructionLock, singleton bool) (func(), error) {
+	frame := frameFrom(ctx)
+
+	c.buildMux.Lock()
+	if frame != nil {
+		if owner := l.owner; singleton && owner != nil && owner.res != frame.res && owner.res.gid != 0 && owner.res.gid == goroutineID() {
+			keys := c.reentrantCycle(owner, frame)
+			c.buildMux.Unlock()
+			return nil, newBeanError(frame.dep.t, frame.dep.name, ErrCircularDependency, "circular dependency detected: %s", strings.Join(keys, " -> "))
+		}
+		if keys := c.waitCycle(frame, l); keys != nil {
+			c.buildMux.Unlock()
+			return nil, newBeanError(frame.dep.t, frame.dep.name, ErrCircularDependency, "circular dependency detected: %s", strings.Join(keys, " -> "))
+		}
+		// The wait is recorded even if the lock is free, since another resolution may take it before this one does.
+		frame.res.waiting, frame.res.waitingOn = frame, l
+	}
+	c.buildMux.Unlock()
+
+	l.mux.Lock()
+
+	c.buildMux.Lock()
+	l.owner = frame
+	if frame != nil {
+		frame.res.waiting, frame.res.waitingOn = nil, nil
+	}
+	held := false
+	if singleton && frame != nil {
+		if frame.res.gid == 0 {
+			frame.res.gid = goroutineID()
+		}
+		if held = frame.res.gid != 0; held {
+			c.held[frame.res.gid] = append(c.held[frame.res.gid], frame)
+		}
+	}
+	c.buildMux.Unlock()
+
+	return func() {
+		c.buildMux.Lock()
+		l.owner = nil
+		if held {
+			c.release(frame)
+		}
+		c.buildMux.Unlock()
+		l.mux.Unlock()
+	}, nil
+}
+
+// release removes the frame from the singleton constructions held by its goroutine. Must be invoked while holding
+// 'buildMux'
+func (c *Container) release(frame *resolutionFrame) {
+	held := c.held[frame.res.gid]
+	for i := len(held) - 1; i >= 0; i-- {
+		if held[i] == frame {
+			held = append(held[:i], held[i+1:]...)
+			break
+		}
+	}
+	if len(held) == 0 {
+		delete(c.held, frame.res.gid)
+	} else {
+		c.held[frame.res.gid] = held
+	}
+}
+
+// reentrantCycle returns the keys of the beans in the cycle closed by the frame, which reaches the singleton being
+// constructed by 'owner' from a resolution started by the same goroutine. The resolutions in between are the ones
+// holding the singletons the goroutine locked after the one of the owner. Must be invoked while holding 'buildMux'
+func (c *Container) reentrantCycle(owner, frame *resolutionFrame) []string {
+	keys := []string{beanKey(owner.dep.t, owner.dep.name)}
+	prev := owner
+	next := func(f *resolutionFrame) {
+		if f.res == prev.res {
+			keys = append(keys, chainKeys(prev, f)[1:]...)
+		} else {
+			// The previous resolution started the next one from its innermost construction.
+			keys = append(keys, chainKeys(prev, prev.res.current)[1:]...)
+			keys = append(keys, chainKeys(nil, f)...)
+		}
+		prev = f
+	}
+
+	held := c.held[owner.res.gid]
+	for i, f := range held {
+		if f == owner {
+			for _, f := range held[i+1:] {
+				next(f)
+			}
+			break
+		}
+	}
+	next(frame)
+	return keys
+}
+
+// waitCycle follows the resolutions waiting on each other, starting from the owner of the lock the frame is about to
+// wait for. If the chain leads back to the resolution of the frame, the keys of the beans in the cycle are returned.
+// Must be invoked while holding 'buildMux'
+func (c *Container) waitCycle(frame *resolutionFrame, l *constructionLock) []string {
+	var hops []string
+	seen := map[*resolution]bool{}
+	for owner := l.owner; owner != nil && !seen[owner.res]; owner = owner.res.waitingOn.owner {
+		if owner.res == frame.res {
+			keys := append(chainKeys(owner, frame.parent), beanKey(frame.dep.t, frame.dep.name))
+			return append(keys, hops...)
+		}
+		seen[owner.res] = true
+		waiting := owner.res.waiting
+		if waiting == nil {
+			return nil
+		}
+		// The bean of the owner frame is already part of the cycle, as the bean the previous resolution waits for.
+		hops = append(hops, chainKeys(owner, waiting.parent)[1:]...)
+		hops = append(hops, beanKey(waiting.dep.t, waiting.dep.name))
+	}
+	return nil
+}
+
+// chainKeys returns the keys of the beans in the chain from the frame 'from' to its descendant 'to', both included. If
+// 'from' is nil, the chain starts at the root of the resolution.
+func chainKeys(from, to *resolutionFrame) []string {
+	var keys []string
+	for f := to; f != nil; f = f.parent {
+		keys = append(keys, beanKey(f.dep.t, f.dep.name))
+		if f == from {
+			break
+		}
+	}
+	for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+		keys[i], keys[j] = keys[j], keys[i]
+	}
+	return keys
+}
+
+// discoverFrom records the bean as a dependency of the bean being constructed by the resolution chain carried by the
+// context, if any. Beans that need construction are recorded when entering their construction, see enterConstruction
+func (c *Container) discoverFrom(ctx context.Context, dep dependency) {
+	parent := frameFrom(ctx)
+	if parent == nil {
+		return
+	}
+	c.buildMux.Lock()
+	defer c.buildMux.Unlock()
+	if !parent.done {
+		c.discover(parent.dep, dep)
+	}
+}
+
+// discover records that the 'from' bean depends on the 'to' bean. Must be invoked while holding 'buildMux'
+func (c *Container) discover(from, to dependency) {
+	for _, d := range c.discovered[from] {
+		if d == to {
+			return
+		}
+	}
+	c.discovered[from] = append(c.discovered[from], to)
+}
+
+// goroutineID returns the id of the current goroutine, or 0 if it cannot be obtained. It is used to tell apart the
+// resolutions started by the constructors that do not resolve their dependencies with the context they receive, such
+// as the functions registered with RegisterFunc. Those resolutions carry no frame, so the context cannot tell whether
+// they are nested in the construction holding a lock, which would never be released, or run by another goroutine that
+// only has to wait for it. Go does not expose goroutine local storage, so the goroutine is the only link left between
+// them.
+//
+// The id is parsed from the header of the stack trace, "goroutine N [status]:", which has been stable since the
+// early releases of Go but is not a documented format. If it cannot be parsed, 0 is returned and the re-entrant
+// constructions are not detected, see acquire. Taking the stack trace is expensive, so it is kept out of the regular
+// resolution path.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := bytes.TrimPrefix(buf[:runtime.Stack(buf[:], false)], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,132 @@
+package beans_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+func TestCircularDependencies(t *testing.T) {
+	Convey("Testing circular dependency detection", t, func() {
+		Convey("Between injected constructors", t, func() {
+			before()
+			ShouldNotError(beans.RegisterConstructor(ComponentType, "bean1", func(other IOther) IService {
+				return &composedService{other: other}
+			}, beans.Singleton()))
+			ShouldNotError(beans.RegisterConstructor((*IOther)(nil), "sql", func(svc IService) IOther {
+				return &OtherImpl1{name: svc.GetName()}
+			}, beans.ParamNames("bean1")))
+
+			_, err := beans.ResolveE(ComponentType, "bean1")
+			ShouldBeTrue(errors.Is(err, beans.ErrCircularDependency))
+			ShouldEqual("circular dependency detected: IService/bean1 -> IOther/sql -> IService/bean1", err.Error())
+
+			// The failed singleton can still be resolved once the cycle is broken.
+			beans.SetAllowOverrides(true)
+			defer beans.SetAllowOverrides(false)
+			ShouldNotError(beans.Register((*IOther)(nil), "sql", &OtherImpl1{name: "sql"}))
+			ShouldEqual("composed-sql", Resolve("bean1").GetName())
+		})
+		Convey("Between functions resolving beans manually", t, func() {
+			before()
+			var inner error
+			ShouldNotError(beans.RegisterFunc(ComponentType, "self", func() interface{} {
+				_, inner = beans.ResolveE(ComponentType, "self")
+				return &TestServiceImpl2{}
+			}, true))
+
+			ShouldNotBeNil(beans.Resolve(ComponentType, "self"))
+			ShouldBeTrue(errors.Is(inner, beans.ErrCircularDependency))
+			ShouldEqual("circular dependency detected: IService/self -> IService/self", inner.Error())
+		})
+		Convey("Between singletons resolving each other without the context", t, func() {
+			before()
+			var inner error
+			ShouldNotError(beans.RegisterFunc(ComponentType, "a", func() interface{} {
+				beans.Resolve((*IOther)(nil), "b")
+				return &TestServiceImpl2{}
+			}, true))
+			ShouldNotError(beans.RegisterFunc((*IOther)(nil), "b", func() interface{} {
+				_, inner = beans.ResolveE(ComponentType, "a")
+				return &OtherImpl1{name: "b"}
+			}, true))
+
+			done := make(chan struct{})
+			go func() {
+				beans.Resolve(ComponentType, "a")
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("the re-entrant construction of the singleton did not finish")
+			}
+			ShouldBeTrue(errors.Is(inner, beans.ErrCircularDependency))
+			ShouldEqual("circular dependency detected: IService/a -> IOther/b -> IService/a", inner.Error())
+		})
+		Convey("Between a constructor and a function resolving it back", t, func() {
+			before()
+			var inner error
+			ShouldNotError(beans.RegisterConstructor(ComponentType, "a", func(other IOther) IService {
+				return &composedService{other: other}
+			}, beans.Singleton(), beans.ParamNames("b")))
+			ShouldNotError(beans.RegisterFunc((*IOther)(nil), "b", func() interface{} {
+				_, inner = beans.ResolveE(ComponentType, "a")
+				return &OtherImpl1{name: "b"}
+			}))
+
+			ShouldNotBeNil(beans.Resolve(ComponentType, "a"))
+			ShouldBeTrue(errors.Is(inner, beans.ErrCircularDependency))
+			ShouldEqual("circular dependency detected: IService/a -> IOther/b -> IService/a", inner.Error())
+		})
+		Convey("Between prototypes", t, func() {
+			before()
+			ShouldNotError(beans.RegisterConstructor(ComponentType, "proto", func(svc IService) IService {
+				return svc
+			}, beans.ParamNames("proto")))
+
+			_, err := beans.ResolveE(ComponentType, "proto")
+			ShouldBeTrue(errors.Is(err, beans.ErrCircularDependency))
+		})
+		Convey("Shared dependencies are not cycles", t, func() {
+			before()
+			ShouldNotError(beans.Register((*IOther)(nil), "shared", &OtherImpl1{name: "shared"}))
+			ShouldNotError(beans.RegisterConstructor(ComponentType, "first", func(other IOther) IService {
+				return &composedService{other: other}
+			}))
+			ShouldNotError(beans.RegisterConstructor(ComponentType, "second", func(first IService, other IOther) IService {
+				return &composedService{other: other}
+			}, beans.ParamNames("first")))
+
+			_, err := beans.ResolveE(ComponentType, "second")
+			ShouldNotError(err)
+		})
+	})
+}
+
+func BenchmarkResolvePrototype(b *testing.B) {
+	c := beans.NewContainer()
+	if err := c.RegisterFunc(ComponentType, "proto", func() interface{} { return &TestServiceImpl2{} }); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Resolve(ComponentType, "proto")
+	}
+}
+
+func BenchmarkResolveSingleton(b *testing.B) {
+	c := beans.NewContainer()
+	if err := c.RegisterFunc(ComponentType, "singleton", func() interface{} { return &TestServiceImpl2{} }, true); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Resolve(ComponentType, "singleton")
+	}
+}
@@ -0,0 +1,171 @@
+package beans
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Scope defines the lifetime of the instances of a bean.
+type Scope int
+
+const (
+	// ScopePrototype creates a new instance every time the bean is resolved.
+	ScopePrototype Scope = iota
+	// ScopeSingleton creates a single instance that is shared by all resolutions of the bean.
+	ScopeSingleton
+	// ScopeContext creates a single instance for each scope context, see NewScope.
+	ScopeContext
+)
+
+var (
+	// ErrNoScope indicates that a context scoped bean was resolved without a scope context. See NewScope
+	ErrNoScope = errors.New("no scope found in the context")
+
+	// ErrScopeEnded indicates that a context scoped bean was resolved with a scope context that already ended.
+	ErrScopeEnded = errors.New("the scope has ended")
+)
+
+func (s Scope) String() string {
+	switch s {
+	case ScopePrototype:
+		return "prototype"
+	case ScopeSingleton:
+		return "singleton"
+	case ScopeContext:
+		return "context"
+	}
+	return fmt.Sprintf("Scope(%d)", int(s))
+}
+
+type scopeCtxKey struct{}
+
+type scopedKey struct {
+	c   *Container
+	dep dependency
+}
+
+// scopedEntry holds the instance of a bean for a scope. The lock serializes the construction of the instance.
+type scopedEntry struct {
+	lock     constructionLock
+	key      scopedKey
+	instance *instanceInfo
+}
+
+// beanScope holds the context scoped instances created for a scope context.
+type beanScope struct {
+	mux     sync.Mutex
+	entries map[scopedKey]*scopedEntry
+	created []*scopedEntry
+	ended   bool
+	once    sync.Once
+}
+
+// NewScope returns a derived context that holds a new scope for context scoped beans (see Scoped). Resolving a context
+// scoped bean with the returned context (see ResolveCtx) creates a single instance of the bean for the scope, which is
+// shared by all the resolutions performed with the same context, such as the ones performed while handling an HTTP
+// request or a job.
+//
+// The scope ends when the returned cancel function is invoked or when the parent context is done. When the scope ends,
+// the scoped instances that implement IStopper or io.Closer are disposed, in the reverse order they were created.
+//
+//   Eg.   ctx, cancel := beans.NewScope(r.Context())
+//         defer cancel()
+//         tx := beans.ResolveCtx(ctx, (*ITransaction)(nil), "").(ITransaction)
+//
+func NewScope(ctx context.Context) (context.Context, context.CancelFunc) {
+	s := &beanScope{entries: map[scopedKey]*scopedEntry{}}
+	ctx, cancel := context.WithCancel(context.WithValue(ctx, scopeCtxKey{}, s))
+	disposeCtx := context.WithoutCancel(ctx)
+	stop := context.AfterFunc(ctx, func() { s.end(disposeCtx) })
+
+	return ctx, func() {
+		cancel()
+		stop()
+		s.end(disposeCtx)
+	}
+}
+
+func scopeFrom(ctx context.Context) *beanScope {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(scopeCtxKey{}).(*beanScope)
+	return s
+}
+
+// scopedInstanceOf returns the instance of a context scoped bean for the scope of the provided context, constructing
+// it if it was not created yet for the scope.
+func (c *Container) scopedInstanceOf(ctx context.Context, dep dependency, ctor *constructorInfo) (*instanceInfo, bool, error) {
+	s := scopeFrom(ctx)
+	if s == nil {
+		return nil, false, ErrNoScope
+	}
+
+	key := scopedKey{c: c, dep: dep}
+	s.mux.Lock()
+	if s.ended {
+		s.mux.Unlock()
+		return nil, false, ErrScopeEnded
+	}
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &scopedEntry{key: key}
+		s.entries[key] = entry
+	}
+	s.mux.Unlock()
+
+	release, err := c.acquire(ctx, &entry.lock, false)
+	if err != nil {
+		return nil, false, err
+	}
+	defer release()
+
+	if entry.instance != nil {
+		return entry.instance, false, nil
+	}
+	instance, err := ctor.construct(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.mux.Lock()
+	if s.ended {
+		s.mux.Unlock()
+		// The scope ended while the instance was constructed, it is disposed right away rather than leaked.
+		disposeScoped(context.WithoutCancel(ctx), &scopedEntry{key: key, instance: instance})
+		return nil, false, ErrScopeEnded
+	}
+	entry.instance = instance
+	s.created = append(s.created, entry)
+	s.mux.Unlock()
+	return instance, true, nil
+}
+
+// end disposes the instances created for the scope, in the reverse order they were created.
+func (s *beanScope) end(ctx context.Context) {
+	s.once.Do(func() {
+		s.mux.Lock()
+		s.ended = true
+		created := s.created
+		s.created = nil
+		s.mux.Unlock()
+
+		for i := len(created) - 1; i >= 0; i-- {
+			disposeScoped(ctx, created[i])
+		}
+	})
+}
+
+// disposeScoped stops the instance of the entry if it implements IStopper or io.Closer, reporting failures to the
+// logger of the container.
+func disposeScoped(ctx context.Context, entry *scopedEntry) {
+	stop := stopFunc(entry.instance.instance)
+	if stop == nil {
+		return
+	}
+	if err := stop(ctx); err != nil {
+		entry.key.c.log().Error(&BeanError{Type: entry.key.dep.t, Name: entry.key.dep.name, Err: err})
+	}
+}
@@ -0,0 +1,116 @@
+package beans_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+type scopedBean struct {
+	OtherImpl1
+	closed *int32
+}
+
+func (s *scopedBean) Close() error {
+	atomic.AddInt32(s.closed, 1)
+	return nil
+}
+
+func TestScopes(t *testing.T) {
+	Convey("Testing context scoped beans", t, func() {
+		Convey("One instance per scope", t, func() {
+			c := beans.NewContainer()
+			var built, closed int32
+			ShouldNotError(c.RegisterConstructor((*IOther)(nil), "scoped", func() IOther {
+				n := atomic.AddInt32(&built, 1)
+				return &scopedBean{OtherImpl1: OtherImpl1{name: string(rune('a' + n - 1))}, closed: &closed}
+			}, beans.Scoped()))
+
+			ctx1, cancel1 := beans.NewScope(context.Background())
+			ctx2, cancel2 := beans.NewScope(context.Background())
+
+			wg := sync.WaitGroup{}
+			for i := 0; i < 16; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					c.ResolveCtx(ctx1, (*IOther)(nil), "scoped")
+				}()
+			}
+			wg.Wait()
+
+			first := c.ResolveCtx(ctx1, (*IOther)(nil), "scoped").(IOther)
+			second := c.ResolveCtx(ctx2, (*IOther)(nil), "scoped").(IOther)
+			ShouldEqual(int32(2), atomic.LoadInt32(&built))
+			ShouldNotBeEqual(first.Name(), second.Name())
+
+			cancel1()
+			cancel1()
+			ShouldEqual(int32(1), atomic.LoadInt32(&closed))
+
+			_, err := c.ResolveCtxE(ctx1, (*IOther)(nil), "scoped")
+			ShouldBeTrue(errors.Is(err, beans.ErrScopeEnded))
+
+			cancel2()
+			ShouldEqual(int32(2), atomic.LoadInt32(&closed))
+		})
+		Convey("Resolving without a scope fails", t, func() {
+			c := beans.NewContainer()
+			ShouldNotError(c.RegisterConstructor((*IOther)(nil), "scoped", func() IOther {
+				return &OtherImpl1{name: "scoped"}
+			}, beans.Scoped()))
+
+			_, err := c.ResolveE((*IOther)(nil), "scoped")
+			ShouldBeTrue(errors.Is(err, beans.ErrNoScope))
+		})
+		Convey("Scoped beans can depend on scoped beans and the context", t, func() {
+			c := beans.NewContainer()
+			type ctxKey struct{}
+			ShouldNotError(c.RegisterConstructor((*IOther)(nil), "request", func(ctx context.Context) IOther {
+				return &OtherImpl1{name: ctx.Value(ctxKey{}).(string)}
+			}, beans.Scoped()))
+			ShouldNotError(c.RegisterConstructor(ComponentType, "handler", func(other IOther) IService {
+				return &composedService{other: other}
+			}, beans.Scoped()))
+
+			ctx, cancel := beans.NewScope(context.WithValue(context.Background(), ctxKey{}, "req-1"))
+			defer cancel()
+			svc := c.ResolveCtx(ctx, ComponentType, "").(IService)
+			ShouldEqual("composed-req-1", svc.GetName())
+			ShouldEqual(c.ResolveCtx(ctx, (*IOther)(nil), "request"), svc.(*composedService).other)
+		})
+		Convey("The scope ends with the parent context", t, func() {
+			c := beans.NewContainer()
+			var closed int32
+			ShouldNotError(c.RegisterConstructor((*IOther)(nil), "scoped", func() IOther {
+				return &scopedBean{closed: &closed}
+			}, beans.Scoped()))
+
+			parent, cancelParent := context.WithCancel(context.Background())
+			ctx, cancel := beans.NewScope(parent)
+			c.ResolveCtx(ctx, (*IOther)(nil), "scoped")
+
+			cancelParent()
+			cancel()
+			ShouldEqual(int32(1), atomic.LoadInt32(&closed))
+		})
+		Convey("Instances constructed after the scope ended are disposed", t, func() {
+			c := beans.NewContainer()
+			var closed int32
+			ctx, cancel := beans.NewScope(context.Background())
+			ShouldNotError(c.RegisterConstructor((*IOther)(nil), "scoped", func() IOther {
+				cancel()
+				return &scopedBean{closed: &closed}
+			}, beans.Scoped()))
+
+			_, err := c.ResolveCtxE(ctx, (*IOther)(nil), "scoped")
+			ShouldBeTrue(errors.Is(err, beans.ErrScopeEnded))
+			ShouldEqual(int32(1), atomic.LoadInt32(&closed))
+		})
+	})
+}
@@ -0,0 +1,164 @@
+package beans
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Snapshot is a copy of the registry of a container, which can be restored into the same container to revert the
+// registrations, overrides, primaries, decorators, post-processors and properties made after it was taken. See
+// Container.Snapshot
+type Snapshot struct {
+	c              *Container
+	allowOverrides bool
+	dependencies   map[reflect.Type]*dependencyCollection
+	properties     map[string]string
+	sources        []*PropertySource
+	decorators     map[reflect.Type][]Decorator
+	processors     []*postProcessor
+	pending        []*pendingBean
+	finalized      bool
+	created        []*createdBean
+	discovered     map[dependency][]dependency
+
+	// instantiated holds the singletons instantiated when the snapshot was taken.
+	instantiated map[*constructorInfo]bool
+
+	// fresh indicates that the beans are restored without their instances, see WithoutInstances
+	fresh bool
+}
+
+// Snapshot takes a copy of the registry of the container. Restoring it with Restore reverts any registration made
+// after the snapshot was taken. The singletons created before the snapshot are kept by the snapshot, use
+// WithoutInstances to restore the registrations without them.
+func (c *Container) Snapshot() *Snapshot {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	s := &Snapshot{
+		c:              c,
+		allowOverrides: c.allowOverrides,
+		dependencies:   copyDependencies(c.dependencies, nil),
+		properties:     copyMap(c.properties),
+		sources:        append([]*PropertySource{}, c.sources...),
+		decorators:     copyMap(c.decorators),
+		processors:     c.processors,
+		pending:        append([]*pendingBean{}, c.pending...),
+		finalized:      c.finalized,
+		instantiated:   map[*constructorInfo]bool{},
+	}
+	for _, dep := range c.dependencies {
+		for _, ctor := range dep.ctors {
+			if ctor.isInstantiated() {
+				s.instantiated[ctor] = true
+			}
+		}
+	}
+
+	c.lifeMux.Lock()
+	for _, bean := range c.created {
+		s.created = append(s.created, &createdBean{dependency: bean.dependency, instance: bean.instance, started: bean.started})
+	}
+	c.lifeMux.Unlock()
+
+	c.buildMux.Lock()
+	s.discovered = copyMap(c.discovered)
+	c.buildMux.Unlock()
+	return s
+}
+
+// WithoutInstances returns a copy of the snapshot that restores every bean as it was registered, without the instances
+// created before the snapshot was taken, so singletons are constructed again once resolved. Each Restore of the
+// returned snapshot restores new copies of the beans, so instances are never shared between restorations.
+func (s *Snapshot) WithoutInstances() *Snapshot {
+	ret := *s
+	ret.created = nil
+	ret.fresh = true
+	return &ret
+}
+
+// Restore reverts the registry of the container to the provided snapshot. The singletons created after the snapshot
+// was taken are discarded, so they are constructed again once resolved and never keep the beans they were injected
+// with, such as a mock registered after the snapshot. The snapshot must have been taken from the same container, since
+// the registered constructors resolve their dependencies from the container they were registered in. The active
+// profiles and the subscribers are not part of the registry and are not restored.
+func (c *Container) Restore(s *Snapshot) error {
+	if s == nil || s.c != c {
+		return errors.New("unable to restore a snapshot taken from a different container")
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.allowOverrides = s.allowOverrides
+	c.dependencies = copyDependencies(s.dependencies, func(ctor *constructorInfo) bool {
+		return s.fresh || (ctor.isInstantiated() && !s.instantiated[ctor])
+	})
+	c.properties = copyMap(s.properties)
+	c.sources = append([]*PropertySource{}, s.sources...)
+	c.decorators = copyMap(s.decorators)
+	c.processors = s.processors
+	c.pending = append([]*pendingBean{}, s.pending...)
+	c.finalized = s.finalized
+
+	c.lifeMux.Lock()
+	c.created = nil
+	for _, bean := range s.created {
+		c.created = append(c.created, &createdBean{dependency: bean.dependency, instance: bean.instance, started: bean.started})
+	}
+	c.lifeMux.Unlock()
+
+	c.buildMux.Lock()
+	c.discovered = copyMap(s.discovered)
+	if c.discovered == nil {
+		c.discovered = map[dependency][]dependency{}
+	}
+	c.buildMux.Unlock()
+	return nil
+}
+
+// copyDependencies copies the registered dependencies. The beans matched by 'reset' are copied without their instances.
+func copyDependencies(deps map[reflect.Type]*dependencyCollection, reset func(ctor *constructorInfo) bool) map[reflect.Type]*dependencyCollection {
+	ret := make(map[reflect.Type]*dependencyCollection, len(deps))
+	for t, dep := range deps {
+		collection := &dependencyCollection{primary: dep.primary, ctors: make(map[string]*constructorInfo, len(dep.ctors))}
+		for name, ctor := range dep.ctors {
+			if reset != nil && reset(ctor) {
+				ctor = ctor.clone()
+			}
+			collection.ctors[name] = ctor
+		}
+		ret[t] = collection
+	}
+	return ret
+}
+
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	ret := make(map[K]V, len(m))
+	for k, v := range m {
+		ret[k] = v
+	}
+	return ret
+}
+
+// clone returns a copy of the registration without its instance and construction stats.
+func (c *constructorInfo) clone() *constructorInfo {
+	ret := &constructorInfo{
+		ctor:          c.ctor,
+		scope:         c.scope,
+		deps:          c.deps,
+		seq:           c.seq,
+		priority:      c.priority,
+		profiles:      c.profiles,
+		conditions:    c.conditions,
+		onMissingBean: c.onMissingBean,
+		beforeInit:    c.beforeInit,
+		afterInit:     c.afterInit,
+		overrides:     c.overrides,
+	}
+	ret.concrete.Store(c.concrete.Load())
+	return ret
+}
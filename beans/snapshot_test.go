@@ -0,0 +1,64 @@
+package beans_test
+
+import (
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+func TestSnapshot(t *testing.T) {
+	Convey("Testing the registry snapshots", t, func() {
+		ref := (*IOther)(nil)
+
+		Convey("Restoring a snapshot reverts the registrations, overrides and primaries made after it", t, func() {
+			c := beans.NewContainer()
+			ShouldNotError(c.Register(ref, "repo", &OtherImpl1{name: "repo"}))
+			ShouldNotError(c.SetPrimary(ref, "repo"))
+			s := c.Snapshot()
+
+			c.SetAllowOverrides(true)
+			ShouldNotError(c.Register(ref, "repo", &OtherImpl1{name: "mock"}))
+			ShouldNotError(c.Register(ref, "other", &OtherImpl1{name: "other"}))
+			ShouldNotError(c.SetPrimary(ref, "other", true))
+			c.SetProperty("key", "value")
+
+			ShouldNotError(c.Restore(s))
+			ShouldEqual("repo", c.Primary(ref).(IOther).Name())
+			ShouldBeFalse(c.Exists(ref, "other"))
+			ShouldBeFalse(c.AllowOverrides())
+			_, ok := c.Property("key")
+			ShouldBeFalse(ok)
+			ShouldError(c.Register(ref, "repo", &OtherImpl1{}))
+		})
+		Convey("Singletons created after the snapshot are discarded", t, func() {
+			c := beans.NewContainer()
+			ShouldNotError(c.RegisterFunc(ref, "before", func() interface{} { return &OtherImpl1{} }, true))
+			ShouldNotError(c.RegisterFunc(ref, "after", func() interface{} { return &OtherImpl1{} }, true))
+			before := c.Resolve(ref, "before")
+			s := c.Snapshot()
+			after := c.Resolve(ref, "after")
+
+			ShouldNotError(c.Restore(s))
+			ShouldEqual(before, c.Resolve(ref, "before"))
+			ShouldBeTrue(after != c.Resolve(ref, "after"))
+		})
+		Convey("Snapshots without instances construct the singletons again on every restore", t, func() {
+			c := beans.NewContainer()
+			ShouldNotError(c.RegisterFunc(ref, "singleton", func() interface{} { return &OtherImpl1{} }, true))
+			first := c.Resolve(ref, "singleton")
+			s := c.Snapshot().WithoutInstances()
+
+			ShouldNotError(c.Restore(s))
+			second := c.Resolve(ref, "singleton")
+			ShouldBeTrue(first != second)
+			ShouldNotError(c.Restore(s))
+			ShouldBeTrue(second != c.Resolve(ref, "singleton"))
+			ShouldEqual(int64(1), c.ListBeans()[0].Resolutions)
+		})
+		Convey("Snapshots can only be restored into the container they were taken from", t, func() {
+			ShouldError(beans.NewContainer().Restore(beans.NewContainer().Snapshot()))
+			ShouldError(beans.NewContainer().Restore(nil))
+		})
+	})
+}
@@ -0,0 +1,183 @@
+// Package typed provides a generic, type-safe API over the beans registry. The bean type is given as a type parameter
+// instead of the '(*IService)(nil)' reference pointer, and resolved beans are returned already typed, so no type
+// assertions are required by the caller.
+//
+// The functions in this package operate over the same default container used by the package level functions of the
+// beans package, so beans registered with one API can be resolved with the other. Each function has a variant suffixed
+// with 'In' that operates over the provided container instead, such as the independent containers used by parallel
+// tests (see beans.NewContainer).
+//
+//	typed.Register[IService]("bean1", &ServiceImpl{})
+//	svc := typed.Resolve[IService]("bean1")
+//
+//	c := beans.NewContainer()
+//	typed.RegisterIn[IService](c, "bean1", &ServiceImpl{})
+//	svc := typed.ResolveIn[IService](c, "bean1")
+package typed
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/jucardi/go-beans/beans"
+)
+
+// Register registers a bean singleton instance into the factory.
+func Register[T any](name string, component T, opts ...beans.Option) error {
+	return RegisterIn[T](beans.Default(), name, component, opts...)
+}
+
+// RegisterIn registers a bean singleton instance into the provided container. See Register
+func RegisterIn[T any](c *beans.Container, name string, component T, opts ...beans.Option) error {
+	return c.RegisterByType(typeOf[T](), name, component, opts...)
+}
+
+// RegisterFunc registers a bean function retriever into the factory. The function could return a singleton instance
+// or could also be used for a constructor. The optional 'singleton' flag has the same meaning as in beans.RegisterFunc
+func RegisterFunc[T any](name string, fn func() T, singleton ...bool) error {
+	return RegisterFuncIn[T](beans.Default(), name, fn, singleton...)
+}
+
+// RegisterFuncIn registers a bean function retriever into the provided container. See RegisterFunc
+func RegisterFuncIn[T any](c *beans.Container, name string, fn func() T, singleton ...bool) error {
+	return c.RegisterFuncByType(typeOf[T](), name, func() interface{} { return fn() }, singleton...)
+}
+
+// Resolve resolves the bean of type T by the given name. Returns the zero value of T if the bean cannot be resolved
+// or if the resolved instance is not a T.
+func Resolve[T any](name string) T {
+	return ResolveIn[T](beans.Default(), name)
+}
+
+// ResolveIn resolves the bean of type T by the given name from the provided container. See Resolve
+func ResolveIn[T any](c *beans.Container, name string) T {
+	return cast[T](c.Get(typeOf[T](), name))
+}
+
+// Primary resolves the bean of type T using the registered bean set as primary. Returns the zero value of T if the
+// bean cannot be resolved or if the resolved instance is not a T.
+func Primary[T any]() T {
+	return PrimaryIn[T](beans.Default())
+}
+
+// PrimaryIn resolves the bean of type T set as primary in the provided container. See Primary
+func PrimaryIn[T any](c *beans.Container) T {
+	return cast[T](c.GetPrimary(typeOf[T]()))
+}
+
+// ResolveE resolves the bean of type T by the given name, returning an error if the bean cannot be resolved or if the
+// resolved instance is not a T. See beans.ResolveE
+func ResolveE[T any](name string) (T, error) {
+	return ResolveEIn[T](beans.Default(), name)
+}
+
+// ResolveEIn resolves the bean of type T by the given name from the provided container, returning an error if the
+// bean cannot be resolved or if the resolved instance is not a T. See ResolveE
+func ResolveEIn[T any](c *beans.Container, name string) (T, error) {
+	return castE[T](c.GetE(typeOf[T](), name))
+}
+
+// PrimaryE resolves the bean of type T using the registered bean set as primary, returning an error if the bean cannot
+// be resolved or if the resolved instance is not a T. See beans.PrimaryE
+func PrimaryE[T any]() (T, error) {
+	return PrimaryEIn[T](beans.Default())
+}
+
+// PrimaryEIn resolves the bean of type T set as primary in the provided container, returning an error if the bean
+// cannot be resolved or if the resolved instance is not a T. See PrimaryE
+func PrimaryEIn[T any](c *beans.Container) (T, error) {
+	return castE[T](c.GetPrimaryE(typeOf[T]()))
+}
+
+// ResolveAll resolves all the beans of type T, sorted by their priority. Beans that are not a T are reported as errors.
+// See beans.ResolveAll
+func ResolveAll[T any]() ([]T, error) {
+	return ResolveAllIn[T](beans.Default())
+}
+
+// ResolveAllIn resolves all the beans of type T from the provided container, sorted by their priority. See ResolveAll
+func ResolveAllIn[T any](c *beans.Container) ([]T, error) {
+	all, err := c.GetAll(typeOf[T]())
+	ret := make([]T, 0, len(all))
+	for _, val := range all {
+		v, castErr := castE[T](val, nil)
+		if castErr != nil {
+			err = errors.Join(err, castErr)
+			continue
+		}
+		ret = append(ret, v)
+	}
+	return ret, err
+}
+
+// ResolveMap resolves all the beans of type T, indexed by their bean names. Beans that are not a T are reported as
+// errors. See beans.ResolveMap
+func ResolveMap[T any]() (map[string]T, error) {
+	return ResolveMapIn[T](beans.Default())
+}
+
+// ResolveMapIn resolves all the beans of type T from the provided container, indexed by their bean names. See
+// ResolveMap
+func ResolveMapIn[T any](c *beans.Container) (map[string]T, error) {
+	all, err := c.GetMap(typeOf[T]())
+	ret := make(map[string]T, len(all))
+	for name, val := range all {
+		v, castErr := castE[T](val, nil)
+		if castErr != nil {
+			err = errors.Join(err, castErr)
+			continue
+		}
+		ret[name] = v
+	}
+	return ret, err
+}
+
+// Decorate registers a decorator for all the beans of type T. See beans.Decorate
+func Decorate[T any](decorator func(inner T) T) error {
+	return DecorateIn[T](beans.Default(), decorator)
+}
+
+// DecorateIn registers a decorator for all the beans of type T in the provided container. See Decorate
+func DecorateIn[T any](c *beans.Container, decorator func(inner T) T) error {
+	return c.DecorateByType(typeOf[T](), func(inner interface{}) interface{} {
+		return decorator(inner.(T))
+	})
+}
+
+// SetPrimary sets the primary bean name to be used for the type T.
+func SetPrimary[T any](name string, replace ...bool) error {
+	return SetPrimaryIn[T](beans.Default(), name, replace...)
+}
+
+// SetPrimaryIn sets the primary bean name to be used for the type T in the provided container.
+func SetPrimaryIn[T any](c *beans.Container, name string, replace ...bool) error {
+	return c.SetPrimaryByType(typeOf[T](), name, replace...)
+}
+
+// Exists indicates if a bean of type T by the given name exists
+func Exists[T any](name string) bool {
+	return ExistsIn[T](beans.Default(), name)
+}
+
+// ExistsIn indicates if a bean of type T by the given name exists in the provided container
+func ExistsIn[T any](c *beans.Container, name string) bool {
+	return c.ExistsByType(typeOf[T](), name)
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func cast[T any](val interface{}) T {
+	ret, _ := val.(T)
+	return ret
+}
+
+func castE[T any](val interface{}, err error) (T, error) {
+	ret, ok := val.(T)
+	if err == nil && !ok {
+		err = fmt.Errorf("the resolved bean of type '%T' cannot be used as '%s'", val, typeOf[T]().String())
+	}
+	return ret, err
+}
@@ -0,0 +1,176 @@
+package typed_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jucardi/go-beans/beans"
+	"github.com/jucardi/go-beans/beans/typed"
+	. "github.com/jucardi/go-testx/testx"
+)
+
+type IGreeter interface {
+	Greet() string
+}
+
+type greeter struct {
+	greeting string
+}
+
+func (g *greeter) Greet() string {
+	return g.greeting
+}
+
+type config struct {
+	Host string
+}
+
+func before() {
+	beans.SetAllowOverrides(true)
+	ShouldNotError(beans.Clear())
+	beans.SetAllowOverrides(false)
+}
+
+func TestRegisterAndResolve(t *testing.T) {
+	Convey("Testing typed Register and Resolve", t, func() {
+		Convey("Interface beans", t, func() {
+			before()
+			ShouldNotError(typed.Register[IGreeter]("hello", &greeter{greeting: "hello"}))
+			ShouldEqual("hello", typed.Resolve[IGreeter]("hello").Greet())
+			ShouldEqual("hello", typed.Primary[IGreeter]().Greet())
+		})
+		Convey("Concrete beans", t, func() {
+			before()
+			ShouldNotError(typed.Register("local", &config{Host: "localhost"}))
+			ShouldEqual("localhost", typed.Resolve[*config]("local").Host)
+		})
+		Convey("Interoperable with the reflection based API", t, func() {
+			before()
+			ShouldNotError(beans.Register((*IGreeter)(nil), "hi", &greeter{greeting: "hi"}))
+			ShouldBeTrue(typed.Exists[IGreeter]("hi"))
+			ShouldEqual("hi", typed.Resolve[IGreeter]("hi").Greet())
+			ShouldEqual("hi", beans.Resolve((*IGreeter)(nil), "hi").(IGreeter).Greet())
+		})
+		Convey("Not found returns the zero value", t, func() {
+			before()
+			ShouldBeNil(typed.Resolve[IGreeter]("missing"))
+			ShouldBeFalse(typed.Exists[IGreeter]("missing"))
+		})
+		Convey("Nil components are rejected", t, func() {
+			before()
+			ShouldError(typed.Register[IGreeter]("nil", nil))
+		})
+	})
+}
+
+func TestRegisterFunc(t *testing.T) {
+	Convey("Testing typed RegisterFunc and SetPrimary", t, func() {
+		before()
+		count := 0
+		ShouldNotError(typed.RegisterFunc("counter", func() IGreeter {
+			count++
+			return &greeter{greeting: "counter"}
+		}, true))
+		ShouldNotError(typed.Register[IGreeter]("other", &greeter{greeting: "other"}))
+		ShouldBeNil(typed.Primary[IGreeter]())
+
+		ShouldNotError(typed.SetPrimary[IGreeter]("counter"))
+		ShouldEqual("counter", typed.Primary[IGreeter]().Greet())
+		ShouldEqual("counter", typed.Resolve[IGreeter]("counter").Greet())
+		ShouldEqual(1, count)
+
+		ShouldError(typed.SetPrimary[IGreeter]("missing"))
+	})
+}
+
+func TestResolveE(t *testing.T) {
+	Convey("Testing typed ResolveE and PrimaryE", t, func() {
+		before()
+		ShouldNotError(typed.Register[IGreeter]("hello", &greeter{greeting: "hello"}))
+
+		val, err := typed.ResolveE[IGreeter]("hello")
+		ShouldNotError(err)
+		ShouldEqual("hello", val.Greet())
+
+		_, err = typed.ResolveE[IGreeter]("missing")
+		ShouldBeTrue(errors.Is(err, beans.ErrBeanNotFound))
+
+		_, err = typed.PrimaryE[*config]()
+		ShouldBeTrue(errors.Is(err, beans.ErrTypeNotRegistered))
+
+		ShouldNotError(beans.RegisterFuncByType(reflect.TypeOf((*IGreeter)(nil)).Elem(), "wrong", func() interface{} {
+			return "not a greeter"
+		}))
+		_, err = typed.ResolveE[IGreeter]("wrong")
+		ShouldError(err)
+	})
+}
+
+func TestResolveAll(t *testing.T) {
+	Convey("Testing typed ResolveAll and ResolveMap", t, func() {
+		before()
+		ShouldNotError(typed.Register[IGreeter]("second", &greeter{greeting: "second"}, beans.Priority(2)))
+		ShouldNotError(typed.Register[IGreeter]("first", &greeter{greeting: "first"}, beans.Priority(1)))
+
+		all, err := typed.ResolveAll[IGreeter]()
+		ShouldNotError(err)
+		ShouldEqual(2, len(all))
+		ShouldEqual("first", all[0].Greet())
+		ShouldEqual("second", all[1].Greet())
+
+		m, err := typed.ResolveMap[IGreeter]()
+		ShouldNotError(err)
+		ShouldEqual("second", m["second"].Greet())
+	})
+}
+
+type loudGreeter struct {
+	IGreeter
+}
+
+func (g *loudGreeter) Greet() string {
+	return g.IGreeter.Greet() + "!"
+}
+
+func TestDecorate(t *testing.T) {
+	Convey("Testing typed Decorate", t, func() {
+		before()
+		ShouldNotError(typed.Register[IGreeter]("hello", &greeter{greeting: "hello"}))
+		ShouldNotError(typed.Decorate(func(inner IGreeter) IGreeter {
+			return &loudGreeter{IGreeter: inner}
+		}))
+		ShouldEqual("hello!", typed.Resolve[IGreeter]("hello").Greet())
+	})
+}
+
+func TestContainerVariants(t *testing.T) {
+	Convey("Testing the typed API over independent containers", t, func() {
+		before()
+		c := beans.NewContainer()
+		ShouldNotError(typed.RegisterIn[IGreeter](c, "hello", &greeter{greeting: "hello"}))
+		ShouldNotError(typed.RegisterFuncIn(c, "hi", func() IGreeter { return &greeter{greeting: "hi"} }))
+		ShouldNotError(typed.SetPrimaryIn[IGreeter](c, "hi"))
+		ShouldNotError(typed.DecorateIn(c, func(inner IGreeter) IGreeter {
+			return &greeter{greeting: inner.Greet() + "!"}
+		}))
+
+		ShouldBeTrue(typed.ExistsIn[IGreeter](c, "hello"))
+		ShouldBeFalse(typed.Exists[IGreeter]("hello"))
+		ShouldEqual("hello!", typed.ResolveIn[IGreeter](c, "hello").Greet())
+		ShouldEqual("hi!", typed.PrimaryIn[IGreeter](c).Greet())
+
+		_, err := typed.ResolveEIn[IGreeter](beans.Default(), "hello")
+		ShouldBeTrue(errors.Is(err, beans.ErrTypeNotRegistered))
+		primary, err := typed.PrimaryEIn[IGreeter](c)
+		ShouldNotError(err)
+		ShouldEqual("hi!", primary.Greet())
+
+		all, err := typed.ResolveAllIn[IGreeter](c)
+		ShouldNotError(err)
+		ShouldEqual(2, len(all))
+		m, err := typed.ResolveMapIn[IGreeter](c)
+		ShouldNotError(err)
+		ShouldEqual("hello!", m["hello"].Greet())
+	})
+}
@@ -1,5 +1,10 @@
 package beans
 
+import (
+	"context"
+	"reflect"
+)
+
 // IResolveHandler defines an optional contract for dependencies to trigger a function on a successful get/resolve
 //
 // The implementation of this interface is optional, the beans manager will verify if this contract is implemented
@@ -45,4 +50,58 @@ type ILogger interface {
 	Info(msg string)
 	// Debug logs a debug message to the logger
 	Debug(msg string)
-}
\ No newline at end of file
+}
+
+// IInitializer defines an optional contract for beans that require an initialization step once they are constructed.
+// Init is invoked right after the constructor returns, before the bean is resolved for the first time. An error
+// returned by Init fails the construction of the bean.
+//
+// The implementation of this interface is optional, the beans manager will verify if this contract is implemented
+// in a bean and trigger the handler(s) accordingly
+//
+type IInitializer interface {
+	Init() error
+}
+
+// IStarter defines an optional contract for singleton beans that need to be started, such as message consumers or
+// background workers. Start is invoked by beans.Start, in the order the singletons were created.
+//
+// The implementation of this interface is optional, the beans manager will verify if this contract is implemented
+// in a bean and trigger the handler(s) accordingly
+//
+type IStarter interface {
+	Start(ctx context.Context) error
+}
+
+// IStopper defines an optional contract for singleton beans that hold resources that must be released, such as
+// connection pools. Stop is invoked by beans.Shutdown, in the reverse order the singletons were created. Beans that
+// implement io.Closer instead are closed by beans.Shutdown as well.
+//
+// The implementation of this interface is optional, the beans manager will verify if this contract is implemented
+// in a bean and trigger the handler(s) accordingly
+//
+type IStopper interface {
+	Stop(ctx context.Context) error
+}
+
+// IOrdered defines an optional contract for beans to define their position when multiple beans of the same type are
+// resolved together, such as with beans.ResolveAll. Beans with a lower order come first. A priority provided at the
+// registration of the bean (see beans.Priority) takes precedence over this contract.
+//
+// The implementation of this interface is optional, the beans manager will verify if this contract is implemented
+// in a bean and trigger the handler(s) accordingly
+//
+type IOrdered interface {
+	Order() int
+}
+
+// IBeanPostProcessor defines a container wide hook invoked for every bean the container constructs (see
+// beans.AddPostProcessor). BeforeInit is invoked once the instance is constructed and its fields are injected, right
+// before IInitializer.Init, and AfterInit right after it. Both of them may replace the instance by returning a
+// different one, which must also implement the bean type. An error returned by any of them fails the construction of
+// the bean.
+//
+type IBeanPostProcessor interface {
+	BeforeInit(t reflect.Type, name string, instance interface{}) (interface{}, error)
+	AfterInit(t reflect.Type, name string, instance interface{}) (interface{}, error)
+}
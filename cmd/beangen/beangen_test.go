@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/jucardi/go-testx/testx"
+)
+
+func errorStrings(errs []error) []string {
+	var ret []string
+	for _, err := range errs {
+		ret = append(ret, err.Error())
+	}
+	return ret
+}
+
+func TestGenerate(t *testing.T) {
+	Convey("Testing the generated wiring", t, func() {
+		patterns := []string{"./testdata/valid/app", "./testdata/valid/store"}
+
+		Convey("The register mode registers the providers as constructors in dependency order", t, func() {
+			file, errs := generate(&config{mode: modeRegister, output: "beans_gen.go", patterns: patterns})
+			ShouldEqual(0, len(errs))
+			ShouldContain(file.path, "testdata/valid/app/beans_gen.go")
+
+			code := string(file.content)
+			ShouldContain(code, "// Code generated by beangen. DO NOT EDIT.\n\npackage app\n")
+			ShouldContain(code, "func RegisterBeans(c *beans.Container) error {")
+			ShouldContain(code, `c.RegisterConstructor((*store.IStore)(nil), "sql", store.NewSQL)`)
+			ShouldContain(code, `c.RegisterConstructor((*IGreeter)(nil), "english", NewEnglish, beans.Singleton())`)
+			ShouldContain(code, `c.RegisterConstructor((*IGreeter)(nil), "spanish", NewSpanish, beans.ParamNames("memory"))`)
+			ShouldContain(code, `c.SetPrimary((*store.IStore)(nil), "sql")`)
+			ShouldBeTrue(strings.Index(code, `"memory", store.NewMemory`) < strings.Index(code, `"english", NewEnglish`))
+		})
+		Convey("The build mode builds the beans with plain Go calls", t, func() {
+			file, errs := generate(&config{mode: modeBuild, output: "beans_gen.go", funcName: "Wire", patterns: patterns})
+			ShouldEqual(0, len(errs))
+
+			code := string(file.content)
+			ShouldContain(code, "func Wire(ctx context.Context, c *beans.Container) error {")
+			ShouldContain(code, "sql, err := store.NewSQL(ctx)")
+			ShouldContain(code, "english := NewEnglish(sql)")
+			ShouldContain(code, "spanish, err := NewSpanish(memory)")
+			ShouldContain(code, `return fmt.Errorf("unable to build the bean 'spanish' of type 'app.IGreeter': %w", err)`)
+			ShouldContain(code, `c.Register((*IGreeter)(nil), "english", english)`)
+		})
+		Convey("Absolute output paths are not relative to the directory", t, func() {
+			output, err := filepath.Abs("testdata/valid/app/beans_gen.go")
+			ShouldNotError(err)
+			file, errs := generate(&config{mode: modeRegister, dir: "testdata", output: output, patterns: []string{"./valid/app", "./valid/store"}})
+			ShouldEqual(0, len(errs))
+			ShouldEqual(output, file.path)
+		})
+	})
+}
+
+func TestGenerateErrors(t *testing.T) {
+	Convey("Testing the errors reported at generation time", t, func() {
+		Convey("Missing providers are reported at the position of the parameter", t, func() {
+			_, errs := generate(&config{output: "beans_gen.go", patterns: []string{"./testdata/missing"}})
+			ShouldEqual(2, len(errs))
+			ShouldContain(errs[0].Error(), "testdata/missing/missing.go:10: missing provider for parameter 0 (missing.IRepo) of missing.NewService")
+			ShouldContain(errs[1].Error(), "testdata/missing/missing.go:15: missing provider for parameter 0 (missing.IService) of missing.NewHandler, no provider named 'cache' is declared")
+		})
+		Convey("Ambiguous primaries are reported with the position of every candidate", t, func() {
+			_, errs := generate(&config{output: "beans_gen.go", patterns: []string{"./testdata/ambiguous"}})
+			ShouldEqual(2, len(errs))
+			ShouldContain(errs[0].Error(), "testdata/ambiguous/ambiguous.go:8: ambiguous primary for type 'ambiguous.IRepo', 2 providers are set as primary")
+			ShouldContain(errs[0].Error(), "'mongo' ambiguous.NewMongo (testdata/ambiguous/ambiguous.go:13)")
+			ShouldContain(errs[1].Error(), "testdata/ambiguous/ambiguous.go:30: ambiguous provider for parameter 0 (ambiguous.IService) of ambiguous.NewHandler")
+		})
+		Convey("Cycles are reported once with the position of every provider in the cycle", t, func() {
+			_, errs := generate(&config{output: "beans_gen.go", patterns: []string{"./testdata/cycle"}})
+			ShouldEqual(1, len(errs))
+			ShouldContain(errs[0].Error(), "testdata/cycle/cycle.go:10: dependency cycle: cycle.NewA (testdata/cycle/cycle.go:10) -> cycle.NewB (testdata/cycle/cycle.go:15) -> cycle.NewC (testdata/cycle/cycle.go:20) -> cycle.NewA")
+		})
+		Convey("Invalid providers and directives are reported", t, func() {
+			errs := errorStrings(func() []error {
+				_, errs := generate(&config{output: "beans_gen.go", patterns: []string{"./testdata/invalid"}})
+				return errs
+			}())
+			ShouldEqual(3, len(errs))
+			ShouldContain(errs[0], "testdata/invalid/invalid.go:8: the provider 'New' must be a function")
+			ShouldContain(errs[1], "testdata/invalid/invalid.go:12: invalid option 'lazy'")
+			ShouldContain(errs[2], "testdata/invalid/invalid.go:18: the provider 'NewVoid' must return the bean instance")
+		})
+	})
+}
+
+func TestRun(t *testing.T) {
+	Convey("Testing the command line", t, func() {
+		var stderr bytes.Buffer
+		ShouldEqual(2, run([]string{"-mode", "lazy"}, &stderr))
+		ShouldContain(stderr.String(), "invalid mode 'lazy'")
+
+		stderr.Reset()
+		ShouldEqual(1, run([]string{"./testdata/cycle"}, &stderr))
+		ShouldContain(stderr.String(), "dependency cycle")
+	})
+}
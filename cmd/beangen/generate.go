@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const beansPkg = "github.com/jucardi/go-beans/beans"
+
+type outputFile struct {
+	path    string
+	content []byte
+}
+
+// generate loads the packages, resolves the providers and returns the generated file, or the errors found.
+func generate(cfg *config) (*outputFile, []error) {
+	pkgs, output, errs := load(cfg)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	var out *packages.Package
+	for _, pkg := range pkgs {
+		if len(pkg.GoFiles) > 0 && filepath.Dir(pkg.GoFiles[0]) == filepath.Dir(output) {
+			out = pkg
+			break
+		}
+	}
+	if out == nil {
+		return nil, []error{fmt.Errorf("the output file '%s' must be in the directory of one of the loaded packages", output)}
+	}
+
+	providers, errs := collectProviders(pkgs)
+	r, regErrs := newRegistry(providers)
+	errs = append(errs, regErrs...)
+	errs = append(errs, r.resolve()...)
+	sorted, cycleErrs := r.order()
+	errs = append(errs, cycleErrs...)
+	for _, p := range providers {
+		if !p.fn.Exported() && p.fn.Pkg().Path() != out.PkgPath {
+			errs = append(errs, errorf(p.pos, "the provider '%s' must be exported to be used from the package '%s'", p.fn.Name(), out.PkgPath))
+		}
+	}
+	if len(providers) == 0 && len(errs) == 0 {
+		errs = append(errs, fmt.Errorf("no providers found, mark the provider functions with the %s directive", directive))
+	}
+	if len(errs) > 0 {
+		sortErrors(errs)
+		return nil, errs
+	}
+
+	g := newGenerator(out)
+	var body bytes.Buffer
+	if cfg.mode == modeBuild {
+		g.build(&body, orDefault(cfg.funcName, "BuildBeans"), sorted)
+	} else {
+		g.register(&body, orDefault(cfg.funcName, "RegisterBeans"), sorted)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by beangen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", out.Name)
+	for _, path := range g.importPaths() {
+		if alias := g.imports[path]; alias != g.pkgNames[path] {
+			fmt.Fprintf(&buf, "\t%s %q\n", alias, path)
+		} else {
+			fmt.Fprintf(&buf, "\t%q\n", path)
+		}
+	}
+	buf.WriteString(")\n")
+	buf.Write(body.Bytes())
+
+	content, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, []error{fmt.Errorf("unable to format the generated code: %w", err)}
+	}
+	return &outputFile{path: output, content: content}, nil
+}
+
+// generator writes the generated code, keeping track of the imported packages.
+type generator struct {
+	out *packages.Package
+	// imports holds the names the imported packages are referenced by, and pkgNames their package names.
+	imports  map[string]string
+	pkgNames map[string]string
+	// names holds the identifiers already in use.
+	names map[string]bool
+}
+
+func newGenerator(out *packages.Package) *generator {
+	g := &generator{out: out, imports: map[string]string{}, pkgNames: map[string]string{}, names: map[string]bool{}}
+	for _, name := range append(out.Types.Scope().Names(), "c", "ctx", "err") {
+		g.names[name] = true
+	}
+	return g
+}
+
+// register writes the function that registers the providers as constructors into a container.
+func (g *generator) register(w *bytes.Buffer, funcName string, providers []*provider) {
+	beans := g.importName(beansPkg, "beans")
+	fmt.Fprintf(w, "\n// %s registers the beans declared with the %s directives into the container as constructors.\n", funcName, directive)
+	fmt.Fprintf(w, "func %s(c *%s.Container) error {\n", funcName, beans)
+	for _, p := range providers {
+		var opts []string
+		if p.singleton {
+			opts = append(opts, beans+".Singleton()")
+		}
+		if names := paramNames(p); names != nil {
+			opts = append(opts, beans+".ParamNames("+strings.Join(names, ", ")+")")
+		}
+		args := append([]string{g.ref(p.typ), strconv.Quote(p.name), g.funcRef(p)}, opts...)
+		fmt.Fprintf(w, "\tif err := c.RegisterConstructor(%s); err != nil {\n\t\treturn err\n\t}\n", strings.Join(args, ", "))
+	}
+	g.primaries(w, providers)
+	w.WriteString("\treturn nil\n}\n")
+}
+
+// build writes the function that builds the beans with plain Go calls and registers the instances into a container.
+func (g *generator) build(w *bytes.Buffer, funcName string, providers []*provider) {
+	beans := g.importName(beansPkg, "beans")
+	context := g.importName("context", "context")
+	fmt.Fprintf(w, "\n// %s builds the beans declared with the %s directives in dependency order, and registers the\n", funcName, directive)
+	fmt.Fprintf(w, "// instances into the container. Every bean is built once.\n")
+	fmt.Fprintf(w, "func %s(ctx %s.Context, c *%s.Container) error {\n", funcName, context, beans)
+
+	vars := map[*provider]string{}
+	for _, p := range providers {
+		vars[p] = g.varName(p.name)
+		var args []string
+		for _, prm := range p.params {
+			if prm.dep == nil {
+				args = append(args, "ctx")
+			} else {
+				args = append(args, vars[prm.dep])
+			}
+		}
+		call := fmt.Sprintf("%s(%s)", g.funcRef(p), strings.Join(args, ", "))
+		if p.returnErr {
+			fmt.Fprintf(w, "\t%s, err := %s\n\tif err != nil {\n", vars[p], call)
+			msg := fmt.Sprintf("unable to build the bean '%s' of type '%s': %%w", strings.ReplaceAll(p.name, "%", "%%"), typeString(p.typ))
+			fmt.Fprintf(w, "\t\treturn %s.Errorf(%s, err)\n\t}\n", g.importName("fmt", "fmt"), strconv.Quote(msg))
+		} else {
+			fmt.Fprintf(w, "\t%s := %s\n", vars[p], call)
+		}
+		fmt.Fprintf(w, "\tif err := c.Register(%s, %q, %s); err != nil {\n\t\treturn err\n\t}\n", g.ref(p.typ), p.name, vars[p])
+	}
+	g.primaries(w, providers)
+	w.WriteString("\treturn nil\n}\n")
+}
+
+func (g *generator) primaries(w *bytes.Buffer, providers []*provider) {
+	for _, p := range providers {
+		if p.primary {
+			fmt.Fprintf(w, "\tif err := c.SetPrimary(%s, %q); err != nil {\n\t\treturn err\n\t}\n", g.ref(p.typ), p.name)
+		}
+	}
+}
+
+// paramNames returns the quoted bean names of the parameters, or nil if every parameter refers to a primary bean.
+func paramNames(p *provider) []string {
+	var ret []string
+	named := false
+	for _, prm := range p.params {
+		ret = append(ret, strconv.Quote(prm.name))
+		named = named || prm.name != ""
+	}
+	if !named {
+		return nil
+	}
+	return ret
+}
+
+// ref returns the nil pointer expression that references the type, eg: '(*alerts.IAlertHandler)(nil)'
+func (g *generator) ref(t types.Type) string {
+	return "(*" + types.TypeString(t, g.qualifier) + ")(nil)"
+}
+
+func (g *generator) funcRef(p *provider) string {
+	if q := g.qualifier(p.fn.Pkg()); q != "" {
+		return q + "." + p.fn.Name()
+	}
+	return p.fn.Name()
+}
+
+func (g *generator) qualifier(pkg *types.Package) string {
+	if pkg.Path() == g.out.PkgPath {
+		return ""
+	}
+	return g.importName(pkg.Path(), pkg.Name())
+}
+
+// importName imports the package and returns the name it is referenced by, which differs from the package name if
+// another imported package has the same name.
+func (g *generator) importName(path, name string) string {
+	if n, ok := g.imports[path]; ok {
+		return n
+	}
+	n := name
+	for i := 2; g.names[n] || token.Lookup(n).IsKeyword(); i++ {
+		n = name + strconv.Itoa(i)
+	}
+	g.imports[path] = n
+	g.pkgNames[path] = name
+	g.names[n] = true
+	return n
+}
+
+// varName returns a unique variable name for the bean by the given name.
+func (g *generator) varName(beanName string) string {
+	var sb strings.Builder
+	upper := false
+	for _, r := range beanName {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_':
+			upper = sb.Len() > 0
+		case upper:
+			sb.WriteRune(unicode.ToUpper(r))
+			upper = false
+		case sb.Len() == 0:
+			sb.WriteRune(unicode.ToLower(r))
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	name := sb.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "bean" + name
+	}
+
+	n := name
+	for i := 2; g.names[n] || token.Lookup(n).IsKeyword() || types.Universe.Lookup(n) != nil; i++ {
+		n = name + strconv.Itoa(i)
+	}
+	g.names[n] = true
+	return n
+}
+
+func (g *generator) importPaths() []string {
+	var ret []string
+	for path := range g.imports {
+		ret = append(ret, path)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func orDefault(val, def string) string {
+	if val == "" {
+		return def
+	}
+	return val
+}
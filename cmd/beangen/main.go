@@ -0,0 +1,79 @@
+// Command beangen generates the wiring of the beans declared with '//beans:provide' directives, so missing providers,
+// ambiguous primaries and dependency cycles are reported when the code is generated rather than when the beans are
+// resolved.
+//
+// A provider is a top-level function that returns the bean, optionally followed by an error. Its parameters are the
+// dependencies of the bean, which are provided by other providers.
+//
+//	//beans:provide name=email primary singleton
+//	func NewEmailHandler(client ISmtpClient) (IAlertHandler, error) {
+//
+// The bean is provided for the type of the first result. The options of the directive are:
+//
+//	name=<name>         the bean name, defaults to the function name
+//	primary             sets the bean as the primary bean of its type
+//	singleton           registers the bean as a singleton, only used by the 'register' mode
+//	params=<a>,<b>,...  the bean names of the parameters in order, an empty name or '_' refers to the primary bean
+//
+// Usage:
+//
+//	beangen [-mode register|build] [-o beans_gen.go] [-func name] [packages]
+//
+// The generated file belongs to the first package loaded, unless the output file is placed in the directory of another
+// of the loaded packages. In the 'register' mode (the default) the generated function registers the providers as
+// constructors into a container. In the 'build' mode the generated function builds every bean once with plain Go calls
+// in dependency order, and registers the built instances into a container.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	modeRegister = "register"
+	modeBuild    = "build"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run runs the generator with the provided command line arguments and returns the exit code.
+func run(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("beangen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cfg := &config{}
+	fs.StringVar(&cfg.mode, "mode", modeRegister, "either 'register' to register the providers as constructors, or 'build' to build the beans with plain Go calls")
+	fs.StringVar(&cfg.output, "o", "beans_gen.go", "the output file, relative to the directory of the first package unless a directory is provided")
+	fs.StringVar(&cfg.funcName, "func", "", "the name of the generated function, defaults to 'RegisterBeans' or 'BuildBeans' depending on the mode")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: beangen [-mode register|build] [-o beans_gen.go] [-func name] [packages]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if cfg.mode != modeRegister && cfg.mode != modeBuild {
+		fmt.Fprintf(stderr, "invalid mode '%s', expected '%s' or '%s'\n", cfg.mode, modeRegister, modeBuild)
+		return 2
+	}
+	if cfg.patterns = fs.Args(); len(cfg.patterns) == 0 {
+		cfg.patterns = []string{"."}
+	}
+
+	file, errs := generate(cfg)
+	for _, err := range errs {
+		fmt.Fprintln(stderr, err)
+	}
+	if len(errs) > 0 {
+		return 1
+	}
+	if err := os.WriteFile(file.path, file.content, 0644); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
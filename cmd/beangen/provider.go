@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const directive = "//beans:provide"
+
+var errorType = types.Universe.Lookup("error").Type()
+
+type config struct {
+	mode     string
+	output   string
+	funcName string
+	patterns []string
+
+	// dir is the directory the packages are loaded from, the current directory if empty.
+	dir string
+}
+
+// provider is a function marked with the '//beans:provide' directive.
+type provider struct {
+	fn        *types.Func
+	pos       token.Position
+	name      string
+	typ       types.Type
+	primary   bool
+	singleton bool
+	returnErr bool
+	params    []*param
+}
+
+// param is a parameter of a provider, which is resolved from the provider of its type by the bean name, or from the
+// primary provider if the name is empty.
+type param struct {
+	index int
+	name  string
+	typ   types.Type
+	pos   token.Position
+
+	// dep is the provider resolved for the parameter, nil for context.Context parameters.
+	dep *provider
+}
+
+func (p *provider) String() string {
+	return p.fn.Pkg().Name() + "." + p.fn.Name()
+}
+
+// posError is an error reported at a position of the source code.
+type posError struct {
+	pos token.Position
+	msg string
+}
+
+func (e *posError) Error() string {
+	return fmt.Sprintf("%s: %s", formatPos(e.pos), e.msg)
+}
+
+func errorf(pos token.Position, format string, args ...interface{}) error {
+	return &posError{pos: pos, msg: fmt.Sprintf(format, args...)}
+}
+
+// formatPos formats the position as 'file:line', where the file is relative to the current directory when possible.
+func formatPos(pos token.Position) string {
+	file := pos.Filename
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+	return fmt.Sprintf("%s:%d", file, pos.Line)
+}
+
+// load loads the packages and returns them along with the output file path. An existing output file is replaced by an
+// empty file of the same package while loading, so a stale generated file never prevents the packages from loading.
+func load(cfg *config) ([]*packages.Package, string, []error) {
+	roots, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedFiles, Dir: cfg.dir}, cfg.patterns...)
+	if err != nil {
+		return nil, "", []error{err}
+	}
+	if len(roots) == 0 || len(roots[0].GoFiles) == 0 {
+		return nil, "", []error{fmt.Errorf("no packages found for %s", strings.Join(cfg.patterns, " "))}
+	}
+
+	output := cfg.output
+	switch {
+	case filepath.IsAbs(output):
+	case filepath.Base(output) == output:
+		output = filepath.Join(filepath.Dir(roots[0].GoFiles[0]), output)
+	default:
+		if output, err = filepath.Abs(filepath.Join(cfg.dir, output)); err != nil {
+			return nil, "", []error{err}
+		}
+	}
+
+	overlay := map[string][]byte{}
+	if f, err := parser.ParseFile(token.NewFileSet(), output, nil, parser.PackageClauseOnly); err == nil {
+		overlay[output] = []byte("package " + f.Name.Name + "\n")
+	}
+
+	mode := packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo
+	pkgs, err := packages.Load(&packages.Config{Mode: mode, Dir: cfg.dir, Overlay: overlay}, cfg.patterns...)
+	if err != nil {
+		return nil, "", []error{err}
+	}
+	var errs []error
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, err := range pkg.Errors {
+			errs = append(errs, err)
+		}
+	})
+	return pkgs, output, errs
+}
+
+// collectProviders returns the providers declared in the packages, in the order they are declared.
+func collectProviders(pkgs []*packages.Package) ([]*provider, []error) {
+	var providers []*provider
+	var errs []error
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Doc == nil {
+					continue
+				}
+				p, err := newProvider(pkg, fn)
+				if err != nil {
+					errs = append(errs, err)
+				} else if p != nil {
+					providers = append(providers, p)
+				}
+			}
+		}
+	}
+	return providers, errs
+}
+
+// newProvider returns the provider declared by the function, or nil if the function has no directive.
+func newProvider(pkg *packages.Package, decl *ast.FuncDecl) (*provider, error) {
+	var comment *ast.Comment
+	for _, c := range decl.Doc.List {
+		if c.Text == directive || strings.HasPrefix(c.Text, directive+" ") {
+			comment = c
+		}
+	}
+	if comment == nil {
+		return nil, nil
+	}
+
+	pos := pkg.Fset.Position(decl.Name.Pos())
+	if decl.Recv != nil {
+		return nil, errorf(pos, "the provider '%s' must be a function, methods cannot be providers", decl.Name.Name)
+	}
+	fn := pkg.TypesInfo.Defs[decl.Name].(*types.Func)
+	sig := fn.Type().(*types.Signature)
+	switch {
+	case sig.TypeParams().Len() > 0:
+		return nil, errorf(pos, "the provider '%s' cannot be generic", decl.Name.Name)
+	case sig.Variadic():
+		return nil, errorf(pos, "the provider '%s' cannot be variadic", decl.Name.Name)
+	case sig.Results().Len() < 1 || sig.Results().Len() > 2 || (sig.Results().Len() == 2 && !types.Identical(sig.Results().At(1).Type(), errorType)):
+		return nil, errorf(pos, "the provider '%s' must return the bean instance, optionally followed by an error", decl.Name.Name)
+	}
+
+	p := &provider{
+		fn:        fn,
+		pos:       pos,
+		name:      decl.Name.Name,
+		typ:       sig.Results().At(0).Type(),
+		returnErr: sig.Results().Len() == 2,
+	}
+	var paramNames []string
+	for _, opt := range strings.Fields(strings.TrimPrefix(comment.Text, directive)) {
+		key, val, hasVal := strings.Cut(opt, "=")
+		switch {
+		case key == "name" && hasVal && val != "":
+			p.name = val
+		case key == "primary" && !hasVal:
+			p.primary = true
+		case key == "singleton" && !hasVal:
+			p.singleton = true
+		case key == "params" && hasVal:
+			paramNames = strings.Split(val, ",")
+		default:
+			return nil, errorf(pkg.Fset.Position(comment.Pos()), "invalid option '%s' of the %s directive", opt, directive)
+		}
+	}
+	if len(paramNames) > sig.Params().Len() {
+		return nil, errorf(pkg.Fset.Position(comment.Pos()), "%d parameter names provided but the provider '%s' has %d parameter(s)", len(paramNames), decl.Name.Name, sig.Params().Len())
+	}
+
+	for i := 0; i < sig.Params().Len(); i++ {
+		v := sig.Params().At(i)
+		prm := &param{index: i, typ: v.Type(), pos: pkg.Fset.Position(v.Pos())}
+		if i < len(paramNames) && paramNames[i] != "_" {
+			prm.name = paramNames[i]
+		}
+		p.params = append(p.params, prm)
+	}
+	return p, nil
+}
+
+func isContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// registry indexes the providers by the type of the bean they provide.
+type registry struct {
+	providers []*provider
+	byType    typeutil.Map
+}
+
+func newRegistry(providers []*provider) (*registry, []error) {
+	r := &registry{providers: providers}
+	var errs []error
+	for _, p := range providers {
+		list, _ := r.byType.At(p.typ).([]*provider)
+		for _, other := range list {
+			if other.name == p.name {
+				errs = append(errs, errorf(p.pos, "a provider named '%s' of type '%s' is already declared at %s", p.name, typeString(p.typ), formatPos(other.pos)))
+			}
+		}
+		r.byType.Set(p.typ, append(list, p))
+	}
+
+	r.byType.Iterate(func(t types.Type, v interface{}) {
+		var primaries []*provider
+		for _, p := range v.([]*provider) {
+			if p.primary {
+				primaries = append(primaries, p)
+			}
+		}
+		if len(primaries) > 1 {
+			errs = append(errs, errorf(primaries[0].pos, "ambiguous primary for type '%s', %d providers are set as primary: %s", typeString(t), len(primaries), describe(primaries)))
+		}
+	})
+	return r, errs
+}
+
+// resolve resolves the parameters of every provider.
+func (r *registry) resolve() []error {
+	var errs []error
+	for _, p := range r.providers {
+		for _, prm := range p.params {
+			if isContext(prm.typ) {
+				continue
+			}
+			dep, err := r.lookup(p, prm)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			prm.dep = dep
+		}
+	}
+	return errs
+}
+
+func (r *registry) lookup(p *provider, prm *param) (*provider, error) {
+	candidates, _ := r.byType.At(prm.typ).([]*provider)
+	target := fmt.Sprintf("parameter %d (%s) of %s", prm.index, typeString(prm.typ), p)
+	if len(candidates) == 0 {
+		return nil, errorf(prm.pos, "missing provider for %s, no provider of type '%s' is declared", target, typeString(prm.typ))
+	}
+	if prm.name != "" {
+		for _, c := range candidates {
+			if c.name == prm.name {
+				return c, nil
+			}
+		}
+		return nil, errorf(prm.pos, "missing provider for %s, no provider named '%s' is declared, found: %s", target, prm.name, describe(candidates))
+	}
+
+	var primaries []*provider
+	for _, c := range candidates {
+		if c.primary {
+			primaries = append(primaries, c)
+		}
+	}
+	switch {
+	case len(primaries) == 1:
+		return primaries[0], nil
+	case len(primaries) > 1:
+		// Already reported by newRegistry
+		return nil, nil
+	case len(candidates) == 1:
+		return candidates[0], nil
+	}
+	return nil, errorf(prm.pos, "ambiguous provider for %s, %d providers of type '%s' are declared and none is set as primary: %s", target, len(candidates), typeString(prm.typ), describe(candidates))
+}
+
+// order returns the providers sorted so every provider comes after the providers it depends on, and reports the
+// dependency cycles found.
+func (r *registry) order() ([]*provider, []error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[*provider]int{}
+	reported := map[string]bool{}
+	var sorted []*provider
+	var errs []error
+	var stack []*provider
+
+	var visit func(p *provider)
+	visit = func(p *provider) {
+		switch state[p] {
+		case visited:
+			return
+		case visiting:
+			for i := range stack {
+				if stack[i] == p {
+					if key, err := cycleError(stack[i:]); !reported[key] {
+						reported[key] = true
+						errs = append(errs, err)
+					}
+					break
+				}
+			}
+			return
+		}
+
+		state[p] = visiting
+		stack = append(stack, p)
+		for _, prm := range p.params {
+			if prm.dep != nil {
+				visit(prm.dep)
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[p] = visited
+		sorted = append(sorted, p)
+	}
+
+	for _, p := range r.providers {
+		visit(p)
+	}
+	return sorted, errs
+}
+
+// cycleError returns a key that identifies the cycle regardless of the provider it starts from, along with the error
+// reporting the cycle.
+func cycleError(cycle []*provider) (string, error) {
+	start := 0
+	for i, p := range cycle {
+		if p.String() < cycle[start].String() {
+			start = i
+		}
+	}
+	var chain, key []string
+	for i := range cycle {
+		p := cycle[(start+i)%len(cycle)]
+		chain = append(chain, fmt.Sprintf("%s (%s)", p, formatPos(p.pos)))
+		key = append(key, p.fn.FullName())
+	}
+	chain = append(chain, cycle[start].String())
+	return strings.Join(key, ","), errorf(cycle[start].pos, "dependency cycle: %s", strings.Join(chain, " -> "))
+}
+
+// describe lists the providers with their positions.
+func describe(providers []*provider) string {
+	var ret []string
+	for _, p := range providers {
+		ret = append(ret, fmt.Sprintf("'%s' %s (%s)", p.name, p, formatPos(p.pos)))
+	}
+	return strings.Join(ret, ", ")
+}
+
+func typeString(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string { return pkg.Name() })
+}
+
+// sortErrors sorts the errors by their position, errors without a position come first.
+func sortErrors(errs []error) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		a, aok := errs[i].(*posError)
+		b, bok := errs[j].(*posError)
+		if !aok || !bok {
+			return !aok && bok
+		}
+		if a.pos.Filename != b.pos.Filename {
+			return a.pos.Filename < b.pos.Filename
+		}
+		return a.pos.Line < b.pos.Line
+	})
+}
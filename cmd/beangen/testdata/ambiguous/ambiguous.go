@@ -0,0 +1,32 @@
+package ambiguous
+
+type IRepo interface{}
+
+type IService interface{}
+
+//beans:provide name=sql primary
+func NewSQL() IRepo {
+	return nil
+}
+
+//beans:provide name=mongo primary
+func NewMongo() IRepo {
+	return nil
+}
+
+//beans:provide name=first
+func NewFirst() IService {
+	return nil
+}
+
+//beans:provide name=second
+func NewSecond() IService {
+	return nil
+}
+
+type IHandler interface{}
+
+//beans:provide name=handler
+func NewHandler(svc IService) IHandler {
+	return nil
+}
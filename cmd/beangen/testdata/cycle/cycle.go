@@ -0,0 +1,22 @@
+package cycle
+
+type IA interface{}
+
+type IB interface{}
+
+type IC interface{}
+
+//beans:provide name=a
+func NewA(b IB) IA {
+	return nil
+}
+
+//beans:provide name=b
+func NewB(c IC) IB {
+	return nil
+}
+
+//beans:provide name=c
+func NewC(a IA) IC {
+	return nil
+}
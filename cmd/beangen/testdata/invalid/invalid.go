@@ -0,0 +1,19 @@
+package invalid
+
+type IRepo interface{}
+
+type repo struct{}
+
+//beans:provide name=repo
+func (r *repo) New() IRepo {
+	return r
+}
+
+//beans:provide name=other lazy
+func NewOther() IRepo {
+	return nil
+}
+
+//beans:provide name=void
+func NewVoid() {
+}
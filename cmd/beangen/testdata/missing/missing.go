@@ -0,0 +1,17 @@
+package missing
+
+type IRepo interface{}
+
+type IService interface{}
+
+type IHandler interface{}
+
+//beans:provide name=service
+func NewService(repo IRepo) IService {
+	return nil
+}
+
+//beans:provide name=handler params=cache
+func NewHandler(svc IService) IHandler {
+	return nil
+}
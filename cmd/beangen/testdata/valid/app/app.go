@@ -0,0 +1,28 @@
+package app
+
+import "github.com/jucardi/go-beans/cmd/beangen/testdata/valid/store"
+
+type IGreeter interface {
+	Greet() string
+}
+
+type greeter struct {
+	greeting string
+	store    store.IStore
+}
+
+func (g *greeter) Greet() string {
+	return g.greeting + " from " + g.store.Get()
+}
+
+// NewEnglish greets in english.
+//
+//beans:provide name=english primary singleton
+func NewEnglish(s store.IStore) IGreeter {
+	return &greeter{greeting: "hello", store: s}
+}
+
+//beans:provide name=spanish params=memory
+func NewSpanish(s store.IStore) (IGreeter, error) {
+	return &greeter{greeting: "hola", store: s}, nil
+}
@@ -0,0 +1,25 @@
+package store
+
+import "context"
+
+type IStore interface {
+	Get() string
+}
+
+type store struct {
+	name string
+}
+
+func (s *store) Get() string {
+	return s.name
+}
+
+//beans:provide name=sql primary
+func NewSQL(ctx context.Context) (IStore, error) {
+	return &store{name: "sql"}, ctx.Err()
+}
+
+//beans:provide name=memory
+func NewMemory() IStore {
+	return &store{name: "memory"}
+}
@@ -0,0 +1,318 @@
+// Package beanscheck provides an analyzer that reports common misuses of the beans API:
+//
+//   - Beans resolved with Resolve, ResolveCtx or Primary, asserted to an interface that the referenced bean type does
+//     not implement, eg: beans.Resolve((*IService)(nil), "name").(IRepo)
+//   - Bean references that are not pointers to interfaces, which make the beans API panic or reference the wrong type,
+//     eg: beans.Resolve(nil, "name") or beans.Resolve(IService(nil), "name"). Pointers to pointers are allowed to
+//     reference the beans registered by their pointer type, such as the config beans.
+//   - Errors returned by the registration functions (Register, RegisterFunc, RegisterConstructor, SetPrimary, etc) that
+//     are ignored.
+//   - Bean names which do not match any of the names registered for the bean type in the analysed packages. Names are
+//     only checked when the bean type is registered with constant names in the analysed package or in one of its
+//     dependencies, since beans are commonly registered by packages that are not visible from the package resolving
+//     them.
+//
+// The analyzer can be run with the beanscheck command, either directly or through go vet:
+//
+//	go vet -vettool=$(which beanscheck) ./...
+package beanscheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	beansPath = "github.com/jucardi/go-beans/beans"
+	typedPath = beansPath + "/typed"
+)
+
+// Analyzer reports misuses of the beans API. See the package documentation for the reported issues.
+var Analyzer = &analysis.Analyzer{
+	Name:      "beanscheck",
+	Doc:       "reports misuses of the beans API: mismatched type assertions of resolved beans, bean references that are not pointers to interfaces, ignored registration errors and unknown bean names",
+	URL:       "https://pkg.go.dev/github.com/jucardi/go-beans/cmd/beanscheck/beanscheck",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{(*registrations)(nil)},
+	Run:       run,
+}
+
+var (
+	// asserted are the functions which result is commonly asserted to the bean type.
+	asserted = map[string]bool{"Resolve": true, "ResolveCtx": true, "Primary": true}
+
+	// mustCheck are the functions which returned error must be checked.
+	mustCheck = map[string]bool{
+		"Register": true, "RegisterByType": true, "RegisterFunc": true, "RegisterFuncByType": true, "RegisterConstructor": true,
+		"RegisterConstructorByType": true, "RegisterConfig": true, "SetPrimary": true, "SetPrimaryByType": true,
+		"RegisterIn": true, "RegisterFuncIn": true, "SetPrimaryIn": true,
+	}
+
+	// registering are the functions that register a bean by name.
+	registering = map[string]bool{"Register": true, "RegisterFunc": true, "RegisterConstructor": true, "RegisterIn": true, "RegisterFuncIn": true}
+
+	// resolving are the functions that require a bean registered by the provided name.
+	resolving = map[string]bool{
+		"Resolve": true, "ResolveE": true, "ResolveCtx": true, "ResolveCtxE": true, "SetPrimary": true,
+		"ResolveIn": true, "ResolveEIn": true, "SetPrimaryIn": true,
+	}
+)
+
+// registrations is the fact exported by the packages that register beans, which holds the bean names registered for
+// each bean type.
+type registrations struct {
+	// Names holds the bean names registered with constant names, by bean type.
+	Names map[string][]string
+	// Dynamic holds the bean types registered with names that are not constant.
+	Dynamic map[string]bool
+}
+
+func (*registrations) AFact() {}
+
+func (r *registrations) String() string {
+	var entries []string
+	for t, names := range r.Names {
+		entries = append(entries, t+": "+strings.Join(names, ", "))
+	}
+	sort.Strings(entries)
+	return "beans registrations {" + strings.Join(entries, "; ") + "}"
+}
+
+func (r *registrations) add(t types.Type, name string, isConst bool) {
+	key := types.TypeString(t, nil)
+	if isConst {
+		r.Names[key] = append(r.Names[key], name)
+	} else {
+		r.Dynamic[key] = true
+	}
+}
+
+func (r *registrations) merge(other *registrations) {
+	for t, names := range other.Names {
+		r.Names[t] = append(r.Names[t], names...)
+	}
+	for t := range other.Dynamic {
+		r.Dynamic[t] = true
+	}
+}
+
+// beanCall is a call to a function of the beans API.
+type beanCall struct {
+	call *ast.CallExpr
+	fn   *types.Func
+	// typ is the bean type, either referenced by a nil pointer or provided as a type argument. Nil if it is not known.
+	typ types.Type
+	// name is the bean name if it is constant.
+	name    string
+	isConst bool
+	hasName bool
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	local := &registrations{Names: map[string][]string{}, Dynamic: map[string]bool{}}
+	var resolved []*beanCall
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		bc := newBeanCall(pass, n.(*ast.CallExpr))
+		if bc == nil {
+			return true
+		}
+		parent := parentOf(n, stack)
+		name := bc.fn.Name()
+
+		if mustCheck[name] && isIgnored(parent) {
+			pass.Reportf(bc.call.Pos(), "the error returned by %s is not checked", qualifiedName(bc.fn))
+		}
+		if ta, ok := parent.(*ast.TypeAssertExpr); ok && asserted[name] && bc.fn.Pkg().Path() == beansPath {
+			checkAssertion(pass, bc, ta)
+		}
+		if bc.typ != nil && bc.hasName && registering[name] {
+			local.add(bc.typ, bc.name, bc.isConst)
+		}
+		if bc.typ != nil && bc.isConst && resolving[name] {
+			resolved = append(resolved, bc)
+		}
+		return true
+	})
+
+	if len(local.Names) > 0 || len(local.Dynamic) > 0 {
+		pass.ExportPackageFact(local)
+	}
+
+	all := &registrations{Names: map[string][]string{}, Dynamic: map[string]bool{}}
+	all.merge(local)
+	for _, fact := range pass.AllPackageFacts() {
+		if fact.Package != pass.Pkg {
+			all.merge(fact.Fact.(*registrations))
+		}
+	}
+	for _, bc := range resolved {
+		checkName(pass, bc, all)
+	}
+	return nil, nil
+}
+
+// newBeanCall returns the call if it calls a function of the beans API, nil otherwise. Invalid bean references are
+// reported.
+func newBeanCall(pass *analysis.Pass, call *ast.CallExpr) *beanCall {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || (fn.Pkg().Path() != beansPath && fn.Pkg().Path() != typedPath) || fn.Pkg() == pass.Pkg {
+		return nil
+	}
+
+	bc := &beanCall{call: call, fn: fn}
+	if fn.Pkg().Path() == typedPath {
+		if inst, ok := pass.TypesInfo.Instances[calleeIdent(call.Fun)]; ok && inst.TypeArgs.Len() > 0 {
+			bc.typ = inst.TypeArgs.At(0)
+		}
+	}
+
+	sig := fn.Type().(*types.Signature)
+	for i := 0; i < sig.Params().Len() && i < len(call.Args); i++ {
+		if sig.Variadic() && i == sig.Params().Len()-1 {
+			break
+		}
+		arg := call.Args[i]
+		switch sig.Params().At(i).Name() {
+		case "ref", "interfaceRef":
+			bc.typ = refType(pass, fn, arg)
+		case "name":
+			bc.hasName = true
+			if tv := pass.TypesInfo.Types[arg]; tv.Value != nil && tv.Value.Kind() == constant.String {
+				bc.name, bc.isConst = constant.StringVal(tv.Value), true
+			}
+		}
+	}
+	return bc
+}
+
+// refType returns the bean type referenced by the nil pointer passed as reference, or nil if it is not known. References
+// that are not pointers to interfaces are reported, except for pointers to pointers which reference the beans
+// registered by their pointer type, such as the config beans (see beans.RegisterConfig).
+func refType(pass *analysis.Pass, fn *types.Func, arg ast.Expr) types.Type {
+	tv := pass.TypesInfo.Types[arg]
+	if tv.IsNil() {
+		pass.Reportf(arg.Pos(), "nil passed as the bean reference of %s, use a nil pointer to the bean interface such as (*IService)(nil)", qualifiedName(fn))
+		return nil
+	}
+	if tv.Type == nil {
+		return nil
+	}
+	if iface, ok := tv.Type.Underlying().(*types.Interface); ok && iface.Empty() {
+		// The reference is passed through an empty interface, its type is only known at runtime.
+		return nil
+	}
+	if ptr, ok := tv.Type.Underlying().(*types.Pointer); ok {
+		if _, isParam := ptr.Elem().(*types.TypeParam); isParam {
+			return nil
+		}
+		if _, isPtr := ptr.Elem().Underlying().(*types.Pointer); isPtr || types.IsInterface(ptr.Elem()) {
+			return ptr.Elem()
+		}
+	}
+	pass.Reportf(arg.Pos(), "the bean reference of %s must be a nil pointer to an interface such as (*IService)(nil), got '%s'", qualifiedName(fn), typeString(tv.Type))
+	return nil
+}
+
+// checkAssertion reports type assertions of the resolved bean that fail for every bean registered for the referenced
+// type: assertions to interfaces the bean type does not implement, and to concrete types that do not implement the
+// bean type.
+func checkAssertion(pass *analysis.Pass, bc *beanCall, ta *ast.TypeAssertExpr) {
+	if bc.typ == nil || ta.Type == nil {
+		return
+	}
+	target := pass.TypesInfo.TypeOf(ta.Type)
+	if target == nil || types.Identical(target, bc.typ) {
+		return
+	}
+	iface, _ := bc.typ.Underlying().(*types.Interface)
+	if types.IsInterface(target) && types.Implements(bc.typ, target.Underlying().(*types.Interface)) {
+		return
+	}
+	if !types.IsInterface(target) && iface != nil && types.Implements(target, iface) {
+		return
+	}
+	pass.Reportf(ta.Type.Pos(), "the bean resolved for '%s' is asserted to '%s', which does not match the bean type", typeString(bc.typ), typeString(target))
+}
+
+// checkName reports bean names that do not match any of the names registered for the bean type.
+func checkName(pass *analysis.Pass, bc *beanCall, all *registrations) {
+	key := types.TypeString(bc.typ, nil)
+	names := all.Names[key]
+	if bc.name == "" || strings.Contains(bc.name, "${") || all.Dynamic[key] || len(names) == 0 {
+		return
+	}
+	for _, name := range names {
+		if name == bc.name {
+			return
+		}
+	}
+	seen := map[string]bool{}
+	var registered []string
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			registered = append(registered, name)
+		}
+	}
+	sort.Strings(registered)
+	pass.Reportf(bc.call.Pos(), "no bean named '%s' is registered for '%s' in the analysed packages, registered: %s", bc.name, typeString(bc.typ), strings.Join(registered, ", "))
+}
+
+// parentOf returns the parent node of the call, skipping parentheses.
+func parentOf(n ast.Node, stack []ast.Node) ast.Node {
+	for i := len(stack) - 2; i >= 0; i-- {
+		if _, ok := stack[i].(*ast.ParenExpr); !ok {
+			return stack[i]
+		}
+	}
+	return nil
+}
+
+// isIgnored indicates whether the result of the call is discarded. Explicit assignments to the blank identifier are
+// considered intentional.
+func isIgnored(parent ast.Node) bool {
+	switch parent.(type) {
+	case *ast.ExprStmt, *ast.GoStmt, *ast.DeferStmt:
+		return true
+	}
+	return false
+}
+
+func calleeIdent(fun ast.Expr) *ast.Ident {
+	switch f := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		return f
+	case *ast.SelectorExpr:
+		return f.Sel
+	case *ast.IndexExpr:
+		return calleeIdent(f.X)
+	case *ast.IndexListExpr:
+		return calleeIdent(f.X)
+	}
+	return nil
+}
+
+// typeString returns the type qualified by the package names, eg: 'alerts.IAlertHandler'
+func typeString(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string { return pkg.Name() })
+}
+
+func qualifiedName(fn *types.Func) string {
+	if sig := fn.Type().(*types.Signature); sig.Recv() != nil {
+		return "Container." + fn.Name()
+	}
+	return fn.Pkg().Name() + "." + fn.Name()
+}
@@ -0,0 +1,12 @@
+package beanscheck_test
+
+import (
+	"testing"
+
+	"github.com/jucardi/go-beans/cmd/beanscheck/beanscheck"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), beanscheck.Analyzer, "registry", "app")
+}
@@ -0,0 +1,105 @@
+package beanscheck_test
+
+import (
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/jucardi/go-testx/testx"
+	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/packages"
+)
+
+var stubbedPackages = []string{"github.com/jucardi/go-beans/beans", "github.com/jucardi/go-beans/beans/typed"}
+
+// TestStubs checks that the stubs of the beans API used by the analyzer tests declare the same signatures as the beans
+// packages, so the analyzer is not tested against an API that no longer exists.
+func TestStubs(t *testing.T) {
+	Convey("Testing the stubs of the beans API match the beans packages", t, func() {
+		root, err := filepath.Abs(filepath.Join("..", "..", ".."))
+		ShouldNotError(err)
+		if _, err := os.Stat(filepath.Join(root, "go.mod")); err != nil {
+			t.Skip("the beans module is not available")
+		}
+
+		testdata := analysistest.TestData()
+		stubs := loadTypes(t, &packages.Config{
+			Dir: testdata,
+			Env: append(os.Environ(), "GOPATH="+testdata, "GO111MODULE=off", "GOFLAGS="),
+		})
+		actual := loadTypes(t, &packages.Config{Dir: root})
+
+		for _, path := range stubbedPackages {
+			stub, pkg := stubs[path], actual[path]
+			for _, name := range stub.Scope().Names() {
+				obj := stub.Scope().Lookup(name)
+				if !obj.Exported() {
+					continue
+				}
+				want := pkg.Scope().Lookup(name)
+				ShouldEqual(describe(want), describe(obj))
+
+				named, ok := obj.Type().(*types.Named)
+				if _, isType := obj.(*types.TypeName); !isType || !ok || want == nil {
+					continue
+				}
+				for i := 0; i < named.NumMethods(); i++ {
+					method := named.Method(i)
+					wantMethod, _, _ := types.LookupFieldOrMethod(types.NewPointer(want.Type()), false, pkg, method.Name())
+					ShouldEqual(describe(wantMethod), describe(method))
+				}
+			}
+		}
+	})
+}
+
+func loadTypes(t *testing.T, cfg *packages.Config) map[string]*types.Package {
+	cfg.Mode = packages.NeedName | packages.NeedTypes
+	pkgs, err := packages.Load(cfg, stubbedPackages...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret := map[string]*types.Package{}
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			t.Fatal(pkg.Errors[0])
+		}
+		ret[pkg.PkgPath] = pkg.Types
+	}
+	return ret
+}
+
+// describe returns the kind and the name of the object, followed by the types of the parameters and results if it is
+// a function. The names of the parameters are not relevant to the analyzer, so they are omitted.
+func describe(obj types.Object) string {
+	if obj == nil {
+		return "<missing>"
+	}
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return fmt.Sprintf("%T %s", obj, obj.Name())
+	}
+	sig := fn.Type().(*types.Signature)
+	qualifier := func(p *types.Package) string { return p.Path() }
+
+	var tparams []string
+	for i := 0; i < sig.TypeParams().Len(); i++ {
+		tparam := sig.TypeParams().At(i)
+		tparams = append(tparams, tparam.Obj().Name()+" "+types.TypeString(tparam.Constraint(), qualifier))
+	}
+	var params []string
+	for i := 0; i < sig.Params().Len(); i++ {
+		params = append(params, types.TypeString(sig.Params().At(i).Type(), qualifier))
+	}
+	if sig.Variadic() {
+		params[len(params)-1] = "..." + strings.TrimPrefix(params[len(params)-1], "[]")
+	}
+	var results []string
+	for i := 0; i < sig.Results().Len(); i++ {
+		results = append(results, types.TypeString(sig.Results().At(i).Type(), qualifier))
+	}
+	return fmt.Sprintf("func %s[%s](%s) (%s)", fn.FullName(), strings.Join(tparams, ", "), strings.Join(params, ", "), strings.Join(results, ", "))
+}
@@ -0,0 +1,82 @@
+package app // want package:"beans registrations {app.IService: svc, svc2, svc4, svc5, svc7, svc6}"
+
+import (
+	"context"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/jucardi/go-beans/beans"
+	"github.com/jucardi/go-beans/beans/typed"
+	"registry"
+)
+
+type IService interface {
+	Find() string
+	io.Closer
+}
+
+type service struct{}
+
+func (s *service) Find() string { return "" }
+
+func (s *service) Close() error { return nil }
+
+func dynamicName() string { return os.Getenv("NAME") }
+
+func assertions() {
+	_ = beans.Resolve((*registry.IRepo)(nil), "sql").(registry.IRepo)
+	_ = beans.Resolve((*registry.IRepo)(nil), "sql").(registry.IOther) // want `the bean resolved for 'registry.IRepo' is asserted to 'registry.IOther', which does not match the bean type`
+	_ = beans.Primary((*IService)(nil)).(io.Closer)
+	_ = beans.Primary((*IService)(nil)).(*service)
+	_ = beans.ResolveCtx(context.Background(), (*IService)(nil), "svc").(*os.File) // want `asserted to '\*os.File'`
+	_ = (beans.Primary((*io.Closer)(nil))).(IService)                              // want `asserted to 'app.IService'`
+	_ = new(beans.Container).Resolve((*IService)(nil), "svc").(registry.IOther)    // want `asserted to 'registry.IOther'`
+	_ = new(beans.Container).Resolve((*IService)(nil), "svc").(registry.IRepo)
+}
+
+func references(ref interface{}) {
+	beans.Resolve(nil, "sql")             // want `nil passed as the bean reference of beans.Resolve`
+	beans.Resolve(IService(nil), "svc")   // want `the bean reference of beans.Resolve must be a nil pointer to an interface such as \(\*IService\)\(nil\), got 'app.IService'`
+	beans.Resolve((*service)(nil), "svc") // want `got '\*app.service'`
+	beans.Exists(service{}, "svc")        // want `got 'app.service'`
+	beans.Resolve(ref, "svc")
+	beans.Resolve((*IService)(nil), "svc")
+	beans.Resolve((**service)(nil), "config")
+}
+
+func errors() error {
+	beans.Register((*IService)(nil), "svc", &service{})                                    // want `the error returned by beans.Register is not checked`
+	beans.RegisterFunc((*IService)(nil), "svc2", func() interface{} { return &service{} }) // want `the error returned by beans.RegisterFunc is not checked`
+	beans.RegisterByType(reflect.TypeOf((*IService)(nil)).Elem(), "svc3", &service{})      // want `the error returned by beans.RegisterByType is not checked`
+	defer beans.SetPrimary((*IService)(nil), "svc")                                        // want `the error returned by beans.SetPrimary is not checked`
+	new(beans.Container).Register((*IService)(nil), "svc4", &service{})                    // want `the error returned by Container.Register is not checked`
+	typed.Register[IService]("svc5", &service{})                                           // want `the error returned by typed.Register is not checked`
+	typed.RegisterIn[IService](new(beans.Container), "svc7", &service{})                   // want `the error returned by typed.RegisterIn is not checked`
+	_ = beans.SetPrimary((*IService)(nil), "svc")
+	if err := beans.Register((*IService)(nil), "svc6", &service{}); err != nil {
+		return err
+	}
+	return beans.SetPrimary((*IService)(nil), "svc")
+}
+
+func names() {
+	beans.Resolve((*registry.IRepo)(nil), "sql")
+	beans.Resolve((*registry.IRepo)(nil), "mysql") // want `no bean named 'mysql' is registered for 'registry.IRepo' in the analysed packages, registered: mongo, sql`
+	beans.ResolveE((*registry.IRepo)(nil), "${repo.type:sql}")
+	beans.Resolve((*IService)(nil), "svc4")
+	beans.Resolve((*IService)(nil), "scv")             // want `no bean named 'scv' is registered for 'app.IService'`
+	_ = typed.Resolve[registry.IRepo]("postgres")      // want `no bean named 'postgres' is registered for 'registry.IRepo'`
+	_ = typed.ResolveIn[registry.IRepo](nil, "redis")  // want `no bean named 'redis' is registered for 'registry.IRepo'`
+	beans.Resolve((*io.Closer)(nil), "not-registered") // unknown types are not checked
+}
+
+type IDynamic interface{}
+
+func dynamic() error {
+	if err := beans.Register((*IDynamic)(nil), dynamicName(), &service{}); err != nil {
+		return err
+	}
+	beans.Resolve((*IDynamic)(nil), "anything")
+	return nil
+}
@@ -0,0 +1,55 @@
+// Package beans is a stub of the beans API used by the analyzer tests.
+package beans
+
+import (
+	"context"
+	"reflect"
+)
+
+type Option func()
+
+type Container struct{}
+
+func Register(interfaceRef interface{}, name string, component interface{}, opts ...Option) error {
+	return nil
+}
+
+func RegisterByType(t reflect.Type, name string, component interface{}, opts ...Option) error {
+	return nil
+}
+
+func RegisterFunc(interfaceRef interface{}, name string, fn func() interface{}, singleton ...bool) error {
+	return nil
+}
+
+func SetPrimary(interfaceRef interface{}, name string, replace ...bool) error {
+	return nil
+}
+
+func Resolve(ref interface{}, name string) interface{} {
+	return nil
+}
+
+func ResolveE(ref interface{}, name string) (interface{}, error) {
+	return nil, nil
+}
+
+func ResolveCtx(ctx context.Context, ref interface{}, name string) interface{} {
+	return nil
+}
+
+func Primary(ref interface{}) interface{} {
+	return nil
+}
+
+func Exists(interfaceRef interface{}, name string) bool {
+	return false
+}
+
+func (c *Container) Register(interfaceRef interface{}, name string, component interface{}, opts ...Option) error {
+	return nil
+}
+
+func (c *Container) Resolve(ref interface{}, name string) interface{} {
+	return nil
+}
@@ -0,0 +1,22 @@
+// Package typed is a stub of the typed beans API used by the analyzer tests.
+package typed
+
+import "github.com/jucardi/go-beans/beans"
+
+func Register[T any](name string, component T, opts ...beans.Option) error {
+	return nil
+}
+
+func Resolve[T any](name string) T {
+	var ret T
+	return ret
+}
+
+func RegisterIn[T any](c *beans.Container, name string, component T, opts ...beans.Option) error {
+	return nil
+}
+
+func ResolveIn[T any](c *beans.Container, name string) T {
+	var ret T
+	return ret
+}
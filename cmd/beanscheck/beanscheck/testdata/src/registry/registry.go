@@ -0,0 +1,24 @@
+package registry // want package:"beans registrations {registry.IRepo: sql, mongo}"
+
+import "github.com/jucardi/go-beans/beans"
+
+type IRepo interface {
+	Find() string
+}
+
+type IOther interface {
+	Other()
+}
+
+type repo struct{}
+
+func (r *repo) Find() string { return "" }
+
+func init() {
+	if err := beans.Register((*IRepo)(nil), "sql", &repo{}); err != nil {
+		panic(err)
+	}
+	if err := beans.Register((*IRepo)(nil), "mongo", &repo{}); err != nil {
+		panic(err)
+	}
+}
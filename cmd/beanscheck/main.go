@@ -0,0 +1,15 @@
+// Command beanscheck reports misuses of the beans API, see the beanscheck package for the reported issues. It can be run
+// directly or through go vet:
+//
+//	beanscheck ./...
+//	go vet -vettool=$(which beanscheck) ./...
+package main
+
+import (
+	"github.com/jucardi/go-beans/cmd/beanscheck/beanscheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(beanscheck.Analyzer)
+}
@@ -1,5 +1,20 @@
 module github.com/jucardi/go-beans
 
-go 1.12
+go 1.21
 
-require github.com/jucardi/go-testx v1.0.9
+require (
+	github.com/jucardi/go-testx v1.0.9
+	gopkg.in/yaml.v2 v2.4.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/jucardi/go-iso8601 v1.0.3 // indirect
+	github.com/jucardi/go-logger-lib v1.0.5 // indirect
+	github.com/jucardi/go-streams v1.0.3 // indirect
+	github.com/jucardi/go-strings v1.0.4 // indirect
+	github.com/jucardi/go-terminal-colors v1.0.2 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/sirupsen/logrus v1.8.1 // indirect
+	golang.org/x/sys v0.0.0-20191026070338-33540a1f6037 // indirect
+)